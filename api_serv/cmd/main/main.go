@@ -21,6 +21,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata"
 )
 
 // @title Shopping list API
@@ -91,6 +93,14 @@ func main() {
 	services := service.NewService(repos)
 	handlers := handler.NewHandler(services, oauthSrv)
 
+	scheduler := service.NewScheduler(repos, time.Minute)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(schedulerCtx)
+		close(schedulerDone)
+	}()
+
 	srv := new(Server.Server)
 	go func() {
 		if err = srv.Run("80", handlers.InitRoutes()); err != nil {
@@ -106,6 +116,9 @@ func main() {
 
 	logrus.Info("Server shutting down")
 
+	stopScheduler()
+	<-schedulerDone
+
 	if err = srv.Shutdown(context.Background()); err != nil {
 		logrus.Errorf("error occured on server shutting down: %s", err.Error())
 	}
@@ -0,0 +1,158 @@
+// Command search finds lines of a file or item titles of a shopping list by queries.
+//
+//	search -file products.txt milk bread
+//	search -api http://localhost -token $TOKEN -list 3 -all milk
+//
+// Queries are read from the arguments or, without them, from the standard input line by line
+// until an empty line. Without -file and -list the lines are read from the standard input first,
+// preceded by their number.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/elecshen/shopping_list/pkg/client"
+	"github.com/elecshen/shopping_list/pkg/search"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const notFound = "Не найдено"
+
+func main() {
+	file := flag.String("file", "", "file with a string per line")
+	api := flag.String("api", "http://localhost", "address of the API server")
+	token := flag.String("token", os.Getenv("SHOPLIST_TOKEN"), "access token, SHOPLIST_TOKEN by default")
+	list := flag.Int("list", 0, "ID of the list to search the items of")
+	all := flag.Bool("all", false, "print all matches instead of the best one")
+	prefix := flag.Bool("prefix", false, "match the beginning of strings only")
+	flag.Parse()
+
+	in := bufio.NewReader(os.Stdin)
+	lines, err := load(in, *file, *api, *token, *list)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	index := search.New(lines)
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	answer := func(query string) {
+		var matches []search.Match
+		if *prefix {
+			matches = index.Prefix(query)
+		} else {
+			matches = index.Search(query)
+		}
+		if len(matches) == 0 {
+			fmt.Fprintln(out, notFound)
+			return
+		}
+		if !*all {
+			matches = matches[:1]
+		}
+		for _, match := range matches {
+			fmt.Fprintln(out, match.Text)
+		}
+	}
+
+	if flag.NArg() > 0 {
+		for _, query := range flag.Args() {
+			answer(query)
+		}
+		return
+	}
+
+	for {
+		query, err := readLine(in)
+		if query == "" {
+			if err != nil && err != io.EOF {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return
+		}
+		answer(query)
+		out.Flush()
+	}
+}
+
+// load reads the strings from the file, the items of the list or the standard input.
+func load(in *bufio.Reader, file, api, token string, list int) ([]string, error) {
+	switch {
+	case file != "" && list != 0:
+		return nil, errors.New("use either -file or -list")
+	case file != "":
+		return readFile(file)
+	case list != 0:
+		return fetchItems(api, token, list)
+	}
+
+	first, err := readLine(in)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || n < 0 {
+		return nil, errors.New("first line must be the number of strings")
+	}
+
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := readLine(in)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func readFile(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// fetchItems returns the titles of the items of the list.
+func fetchItems(api, token string, list int) ([]string, error) {
+	if token == "" {
+		return nil, errors.New("access token is required to fetch a list, use -token or SHOPLIST_TOKEN")
+	}
+
+	items, err := client.New(api, client.WithToken(token)).Items(context.Background(), list)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+
+	return titles, nil
+}
+
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
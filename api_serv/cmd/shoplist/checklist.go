@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elecshen/shopping_list/pkg/client"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const checklistHelp = `Type item numbers to check them off or uncheck them, several separated by spaces,
+"+ title" to add an item, "r" to reload the list and "q" to quit.`
+
+// checklist shows the items of the list numbered and toggles the ones the user types the numbers of.
+func (a *app) checklist(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := a.requireToken(); err != nil {
+		return err
+	}
+	listId, err := parseId(args[0])
+	if err != nil {
+		return err
+	}
+
+	list, err := a.client.List(ctx, listId)
+	if err != nil {
+		return err
+	}
+	items, err := a.client.Items(ctx, listId)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(checklistHelp)
+	for {
+		printChecklist(list, items)
+
+		line, err := a.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "q":
+			return nil
+		case line == "r" || line == "":
+		case strings.HasPrefix(line, "+"):
+			title := strings.TrimSpace(strings.TrimPrefix(line, "+"))
+			if title == "" {
+				fmt.Fprintln(os.Stderr, "item has no title")
+				continue
+			}
+			_, warning, err := a.client.CreateItem(ctx, listId, client.Item{Title: title})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			} else if warning != "" {
+				fmt.Fprintln(os.Stderr, "Warning:", warning)
+			}
+		default:
+			if err = toggleItems(ctx, a.client, items, strings.FieldsFunc(line, isSeparator)); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+
+		if items, err = a.client.Items(ctx, listId); err != nil {
+			return err
+		}
+	}
+}
+
+// toggleItems checks off or unchecks the items with the numbers.
+func toggleItems(ctx context.Context, c *client.Client, items []client.Item, numbers []string) error {
+	var errs []error
+	for _, number := range numbers {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 1 || n > len(items) {
+			errs = append(errs, errors.New("no item "+number))
+			continue
+		}
+
+		item := items[n-1]
+		if err = c.CheckItem(ctx, item.Id, !item.Checked); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Title, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func printChecklist(list client.List, items []client.Item) {
+	checked := 0
+	for _, item := range items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	fmt.Printf("\n%s (%d/%d)\n", list.Title, checked, len(items))
+	for i, item := range items {
+		line := fmt.Sprintf("%3d %s %s", i+1, checkbox(item.Checked), item.Title)
+		if item.Quantity != 1 || item.Unit != "pcs" {
+			line += ", " + quantity(item)
+		}
+		fmt.Println(line)
+	}
+	fmt.Print("> ")
+}
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == ',' || r == '\t'
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/elecshen/shopping_list/pkg/client"
+	"golang.org/x/term"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func (a *app) login(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	api := flags.String("api", a.cfg.API, "address of the API server")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	username := flags.Arg(0)
+	var err error
+	if username == "" {
+		if username, err = a.prompt("Username: "); err != nil {
+			return err
+		}
+	}
+	password, err := a.promptPassword("Password: ")
+	if err != nil {
+		return err
+	}
+
+	c := client.New(*api)
+	token, err := c.SignIn(ctx, username, password)
+	if err != nil {
+		return err
+	}
+
+	a.cfg.API, a.cfg.Username, a.cfg.Token = *api, username, token
+	if err = saveConfig(a.cfgPath, a.cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Signed in as %s, the token is stored in %s\n", username, a.cfgPath)
+	return nil
+}
+
+func (a *app) lists(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	if err := a.requireToken(); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "ls":
+		flags := flag.NewFlagSet("lists ls", flag.ContinueOnError)
+		archived := flags.Bool("archived", false, "show archived lists")
+		if err := flags.Parse(args[1:]); err != nil {
+			return errUsage
+		}
+
+		lists, err := a.client.Lists(ctx, archived)
+		if err != nil {
+			return err
+		}
+		return a.out.lists(lists)
+
+	case "create":
+		flags := flag.NewFlagSet("lists create", flag.ContinueOnError)
+		description := flags.String("d", "", "description of the list")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() == 0 {
+			return errUsage
+		}
+
+		id, err := a.client.CreateList(ctx, client.List{Title: strings.Join(flags.Args(), " "), Description: *description})
+		if err != nil {
+			return err
+		}
+		return a.out.id("created list", id)
+
+	case "rm":
+		return a.eachId(args[1:], "deleted list", func(id int) error {
+			return a.client.DeleteList(ctx, id, 0)
+		})
+	}
+
+	return errUsage
+}
+
+func (a *app) items(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	if err := a.requireToken(); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "ls":
+		if len(args) != 2 {
+			return errUsage
+		}
+		listId, err := parseId(args[1])
+		if err != nil {
+			return err
+		}
+
+		items, err := a.client.Items(ctx, listId)
+		if err != nil {
+			return err
+		}
+		return a.out.items(items)
+
+	case "add":
+		flags := flag.NewFlagSet("items add", flag.ContinueOnError)
+		quantity := flags.Float64("q", 0, "quantity, 1 by default")
+		unit := flags.String("u", "", "unit, pieces by default")
+		category := flags.String("c", "", "category, guessed by the title by default")
+		description := flags.String("d", "", "description of the item")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() < 2 {
+			return errUsage
+		}
+		listId, err := parseId(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		item := client.Item{Title: strings.Join(flags.Args()[1:], " "), Description: *description,
+			Quantity: *quantity, Unit: *unit, Category: *category}
+		id, warning, err := a.client.CreateItem(ctx, listId, item)
+		if err != nil {
+			return err
+		}
+		if warning != "" {
+			fmt.Fprintln(os.Stderr, "Warning:", warning)
+		}
+		return a.out.id("added item", id)
+
+	case "check", "uncheck":
+		checked := args[0] == "check"
+		return a.eachId(args[1:], args[0]+"ed item", func(id int) error {
+			return a.client.CheckItem(ctx, id, checked)
+		})
+
+	case "rm":
+		return a.eachId(args[1:], "deleted item", func(id int) error {
+			return a.client.DeleteItem(ctx, id, 0)
+		})
+	}
+
+	return errUsage
+}
+
+// eachId applies the action to every ID argument and prints the IDs it succeeded for.
+func (a *app) eachId(args []string, done string, action func(id int) error) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := parseId(arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	for _, id := range ids {
+		if err := action(id); err != nil {
+			return fmt.Errorf("%d: %w", id, err)
+		}
+		if err := a.out.id(done, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *app) requireToken() error {
+	if a.cfg.Token == "" {
+		return errors.New("not signed in, run shoplist login")
+	}
+	return nil
+}
+
+// prompt asks for a line on the standard input.
+func (a *app) prompt(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	line, err := a.in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" && err != nil {
+		return "", err
+	}
+
+	return line, nil
+}
+
+// promptPassword asks for a line on the standard input without echoing it when it is a terminal.
+func (a *app) promptPassword(label string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return a.prompt(label)
+	}
+
+	fmt.Fprint(os.Stderr, label)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(password)), nil
+}
+
+func parseId(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid id " + arg)
+	}
+	return id, nil
+}
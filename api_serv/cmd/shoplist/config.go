@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const defaultAPI = "http://localhost"
+
+// config is stored as JSON readable by the user only.
+type config struct {
+	API      string `json:"api"`
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// configPath returns the path of the config file: SHOPLIST_CONFIG or shoplist/config.json
+// in the user config directory.
+func configPath() (string, error) {
+	if path := os.Getenv("SHOPLIST_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "shoplist", "config.json"), nil
+}
+
+// loadConfig reads the config file, a missing file is an empty config.
+func loadConfig(path string) (config, error) {
+	cfg := config{API: defaultAPI}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, errors.New("invalid config " + path + ": " + err.Error())
+	}
+	if cfg.API == "" {
+		cfg.API = defaultAPI
+	}
+
+	return cfg, nil
+}
+
+// saveConfig replaces the config file by a new one readable by the user only, a file written
+// in place would keep the mode it was created with.
+func saveConfig(path string, cfg config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// the temporary file is created with the mode 0600
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err = file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return nil
+}
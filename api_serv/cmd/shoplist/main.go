@@ -0,0 +1,112 @@
+// Command shoplist manages shopping lists from the terminal.
+//
+//	shoplist login [-api URL] [username]
+//	shoplist lists ls [-archived]
+//	shoplist lists create [-d description] title
+//	shoplist lists rm id...
+//	shoplist items ls list
+//	shoplist items add [-q quantity] [-u unit] [-c category] [-d description] list title
+//	shoplist items check|uncheck|rm id...
+//	shoplist checklist list
+//
+// The token of the login is stored in the config file, shoplist/config.json in the user
+// config directory or SHOPLIST_CONFIG. Results are printed as tables or, with -o json, as JSON.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/elecshen/shopping_list/pkg/client"
+	"os"
+	"os/signal"
+)
+
+var errUsage = errors.New("usage")
+
+const usage = `Usage: shoplist [-o table|json] command [arguments]
+
+Commands:
+  login [-api URL] [username]      sign in and store the token
+  lists ls [-archived]             show lists
+  lists create [-d text] title     create a list
+  lists rm id...                   delete lists
+  items ls list                    show items of a list
+  items add [-q n] [-u unit] [-c category] [-d text] list title
+                                   add an item to a list
+  items check id...                check items off
+  items uncheck id...              uncheck items
+  items rm id...                   delete items
+  checklist list                   check items off interactively
+`
+
+// app holds what commands share.
+type app struct {
+	cfg     config
+	cfgPath string
+	client  *client.Client
+	out     printer
+	in      *bufio.Reader
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	format := flag.String("o", formatTable, "output format, table or json")
+	flag.Parse()
+
+	if err := run(*format, flag.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "shoplist:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format string, args []string) error {
+	if format != formatTable && format != formatJSON {
+		return errors.New("unknown output format " + format)
+	}
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	a := &app{
+		cfg:     cfg,
+		cfgPath: path,
+		client:  client.New(cfg.API, client.WithToken(cfg.Token)),
+		out:     printer{w: os.Stdout, format: format},
+		in:      bufio.NewReader(os.Stdin),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "login":
+		return a.login(ctx, args)
+	case "lists":
+		return a.lists(ctx, args)
+	case "items":
+		return a.items(ctx, args)
+	case "checklist":
+		return a.checklist(ctx, args)
+	}
+
+	return errUsage
+}
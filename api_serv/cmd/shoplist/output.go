@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/elecshen/shopping_list/pkg/client"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer writes results as aligned tables or as JSON.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func (p printer) json(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p printer) table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (p printer) lists(lists []client.List) error {
+	if p.format == formatJSON {
+		return p.json(lists)
+	}
+
+	rows := make([][]string, 0, len(lists))
+	for _, list := range lists {
+		flags := make([]string, 0, 2)
+		if list.Pinned {
+			flags = append(flags, "pinned")
+		}
+		if list.Archived {
+			flags = append(flags, "archived")
+		}
+		rows = append(rows, []string{strconv.Itoa(list.Id), list.Title, list.Description, strings.Join(flags, ",")})
+	}
+
+	return p.table([]string{"ID", "TITLE", "DESCRIPTION", "FLAGS"}, rows)
+}
+
+func (p printer) items(items []client.Item) error {
+	if p.format == formatJSON {
+		return p.json(items)
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []string{strconv.Itoa(item.Id), checkbox(item.Checked), item.Title,
+			quantity(item), item.Category, item.Description})
+	}
+
+	return p.table([]string{"ID", "", "TITLE", "QUANTITY", "CATEGORY", "DESCRIPTION"}, rows)
+}
+
+// id prints the ID of a created or changed object.
+func (p printer) id(action string, id int) error {
+	if p.format == formatJSON {
+		return p.json(map[string]int{"id": id})
+	}
+
+	_, err := fmt.Fprintf(p.w, "%s %d\n", action, id)
+	return err
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func quantity(item client.Item) string {
+	return strconv.FormatFloat(item.Quantity, 'f', -1, 64) + " " + item.Unit
+}
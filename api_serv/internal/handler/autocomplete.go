@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+)
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Autocomplete item title
+// @Description 		Returns titles of items the user has added before that start with the query, have a word starting with it or are similar to it, with the category, quantity, unit and price of the latest item. Titles are ranked by the match, the number of uses and how recently they were used
+// @Accept				json
+// @Produce  			json
+// @Param 				q					query		string	true				"Typed part of the title"
+// @Param 				limit				query		int		false				"Number of titles, 10 by default and 50 at most"
+// @Success 			200 				{array} 	model.Completion		"Collection of titles"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to get titles"
+// @Router 				/api/autocomplete 	[get]
+func (h *Handler) autocomplete(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		newErrorResponse(c, http.StatusBadRequest, "invalid q param")
+		return
+	}
+
+	limit := defaultAutocompleteLimit
+	if param := c.Query("limit"); param != "" {
+		limit, err = strconv.Atoi(param)
+		if err != nil || limit < 1 || limit > maxAutocompleteLimit {
+			newErrorResponse(c, http.StatusBadRequest, "invalid limit param")
+			return
+		}
+	}
+
+	completions, err := h.services.Autocomplete.Complete(userId, query, limit)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, completions)
+}
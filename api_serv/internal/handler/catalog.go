@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Get units
+// @Description 		Returns the catalog of item units, quantities convert between units of the same dimension
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	model.Unit				"Collection of units"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Router 				/api/units 			[get]
+func (h *Handler) getUnits(c *gin.Context) {
+	c.JSON(http.StatusOK, model.Units())
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Get categories
+// @Description 		Returns the built-in item categories in the order a typical store is walked through
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	string					"Collection of categories"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Router 				/api/categories 	[get]
+func (h *Handler) getCategories(c *gin.Context) {
+	c.JSON(http.StatusOK, model.Categories())
+}
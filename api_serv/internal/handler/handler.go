@@ -74,11 +74,28 @@ func (h *Handler) InitRoutes() *gin.Engine {
 			lists.GET("/:id", h.getListById)
 			lists.PUT("/:id", h.updateList)
 			lists.DELETE("/:id", h.deleteList)
+			lists.POST("/:id/archive", h.archiveList)
+			lists.POST("/:id/unarchive", h.unarchiveList)
+			lists.PUT("/order", h.reorderLists)
+			lists.POST("/merge", h.mergeLists)
+			lists.POST("/:id/duplicate", h.duplicateList)
+			lists.POST("/:id/split", h.splitList)
+			lists.POST("/:id/trips", h.startTrip)
+			lists.GET("/:id/suggestions", h.getSuggestions)
+			lists.POST("/:id/suggestions", h.addSuggestion)
 
 			items := lists.Group(":id/items")
 			{
 				items.POST("/", h.createItem)
 				items.GET("/", h.getAllItems)
+				items.POST("/bulk", h.bulkItems)
+				items.POST("/quick", h.quickAddItems)
+			}
+
+			snapshots := lists.Group(":id/snapshots")
+			{
+				snapshots.POST("/", h.createSnapshot)
+				snapshots.GET("/", h.getAllSnapshots)
 			}
 		}
 
@@ -87,6 +104,76 @@ func (h *Handler) InitRoutes() *gin.Engine {
 			items.GET("/:id", h.getItemById)
 			items.PUT("/:id", h.updateItem)
 			items.DELETE("/:id", h.deleteItem)
+			items.POST("/move", h.moveItems)
+			items.POST("/copy", h.copyItems)
+			items.PUT("/:id/position", h.setItemPosition)
+			items.POST("/:id/move", h.moveItem)
+			items.POST("/:id/copy", h.copyItem)
+		}
+
+		templates := api.Group("/templates")
+		{
+			templates.POST("/", h.createTemplate)
+			templates.GET("/", h.getAllTemplates)
+			templates.GET("/:id", h.getTemplateById)
+			templates.PUT("/:id", h.updateTemplate)
+			templates.DELETE("/:id", h.deleteTemplate)
+			templates.POST("/:id/instantiate", h.instantiateTemplate)
+		}
+
+		schedules := api.Group("/schedules")
+		{
+			schedules.POST("/", h.createSchedule)
+			schedules.GET("/", h.getAllSchedules)
+			schedules.GET("/:id", h.getScheduleById)
+			schedules.PUT("/:id", h.updateSchedule)
+			schedules.DELETE("/:id", h.deleteSchedule)
+			schedules.GET("/:id/preview", h.previewSchedule)
+		}
+
+		stores := api.Group("/stores")
+		{
+			stores.POST("/", h.createStore)
+			stores.GET("/", h.getAllStores)
+			stores.GET("/:id", h.getStoreById)
+			stores.PUT("/:id", h.updateStore)
+			stores.DELETE("/:id", h.deleteStore)
+		}
+
+		trips := api.Group("/trips")
+		{
+			trips.GET("/", h.getAllTrips)
+			trips.GET("/:id", h.getTripById)
+			trips.POST("/:id/finish", h.finishTrip)
+		}
+
+		reports := api.Group("/reports")
+		{
+			reports.GET("/spending", h.getSpendingReport)
+		}
+
+		products := api.Group("/products")
+		{
+			products.GET("/", h.getAllProducts)
+			products.GET("/:id/prices", h.getProductPrices)
+		}
+
+		api.GET("/units", h.getUnits)
+		api.GET("/categories", h.getCategories)
+		api.GET("/autocomplete", h.autocomplete)
+		api.GET("/search", h.search)
+
+		sync := api.Group("/sync")
+		{
+			sync.GET("/", h.pullChanges)
+			sync.POST("/", h.pushChanges)
+		}
+
+		snapshots := api.Group("/snapshots")
+		{
+			snapshots.GET("/:id", h.getSnapshotById)
+			snapshots.GET("/:id/diff", h.diffSnapshot)
+			snapshots.POST("/:id/restore", h.restoreSnapshot)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -10,14 +11,15 @@ import (
 // @Tags 				Api Item
 // @Security 			JWTAuth
 // @Summary 			Create item
-// @Description 		Creates a new item of shopping list and returns its ID
+// @Description 		Creates a new item of shopping list and returns its ID, the same unchecked product in a compatible unit gets the quantity added instead. The estimated price defaults to the latest recorded price of the product. A warning is returned when the list exceeds its budget
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true					"ID of list"
-// @Param 				ItemInfo			body		model.ShoppingItem	true	"Title and description of item"
-// @Success 			200 				{object} 	okResponse			 		"Object id"
+// @Param 				ItemInfo			body		model.ShoppingItem	true	"Title, description, quantity, unit, category and prices of item"
+// @Success 			200 				{object} 	itemResponse		 		"Object id and budget warning"
 // @Failure				401					{object}	errorResponse				"Unauthorized"
 // @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				409					{object}	errorResponse				"Item kept changing while the quantity was added"
 // @Failure				500					{object}	errorResponse				"Failed to create item"
 // @Router 				/api/lists/{ID}/items 			[post]
 func (h *Handler) createItem(c *gin.Context) {
@@ -39,22 +41,29 @@ func (h *Handler) createItem(c *gin.Context) {
 	}
 
 	id, err := h.services.Item.Create(userId, listId, input)
+	if errors.Is(err, model.ErrVersionMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, okResponse{Id: id})
+	c.JSON(http.StatusOK, itemResponse{Id: id, Warning: h.budgetWarning(userId, listId)})
 }
 
 // @Tags 				Api Item
 // @Security 			JWTAuth
 // @Summary 			Get all items of the list
-// @Description 		Returns a collection of items
+// @Description 		Returns a collection of items in user-defined order, grouped by category with group=category or in the walking order of the list store with order=store
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true					"ID of list"
+// @Param 				group				query		string	false				"category to group items by category"
+// @Param 				order				query		string	false				"store to sort items in the walking order of the list store"
 // @Success 			200 				{array} 	model.ShoppingItem			"Collection of items"
+// @Success 			200 				{array} 	model.ItemCategoryGroup		"Collection of category groups"
 // @Failure				401					{object}	errorResponse				"Unauthorized"
 // @Failure				400					{object}	errorResponse				"Invalid params"
 // @Failure				500					{object}	errorResponse				"Failed to get items"
@@ -71,13 +80,47 @@ func (h *Handler) getAllItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.services.Item.GetAll(userId, listId)
-	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+	group, order := c.Query("group"), c.Query("order")
+	if order != "" && order != "store" {
+		newErrorResponse(c, http.StatusBadRequest, "invalid order param")
+		return
+	}
+	if order != "" && group != "" {
+		newErrorResponse(c, http.StatusBadRequest, "group and order params cannot be combined")
 		return
 	}
 
-	c.JSON(http.StatusOK, items)
+	switch group {
+	case "":
+		if order == "store" {
+			items, err := h.services.Item.GetAllInStoreOrder(userId, listId)
+			if err != nil {
+				newErrorResponse(c, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			c.JSON(http.StatusOK, items)
+			return
+		}
+
+		items, err := h.services.Item.GetAll(userId, listId)
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, items)
+	case "category":
+		groups, err := h.services.Item.GetAllByCategory(userId, listId)
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, groups)
+	default:
+		newErrorResponse(c, http.StatusBadRequest, "invalid group param")
+	}
 }
 
 // @Tags 				Api Item
@@ -87,7 +130,9 @@ func (h *Handler) getAllItems(c *gin.Context) {
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true						"ID of item"
+// @Param 				If-None-Match		header		string	false					"ETag of the cached item"
 // @Success 			200 				{object} 	okResponse						"Object id"
+// @Success 			304 				"Cached item is up to date"
 // @Failure				401					{object}	errorResponse					"Unauthorized"
 // @Failure				400					{object}	errorResponse					"Invalid params"
 // @Failure				500					{object}	errorResponse					"Failed to get the item"
@@ -110,6 +155,11 @@ func (h *Handler) getItemById(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, formatETag(item.Version)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, item)
 }
 
@@ -120,10 +170,13 @@ func (h *Handler) getItemById(c *gin.Context) {
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true						"ID of item"
-// @Param 				ListInfo			body		model.UpdateItemInput	true	"Title or/and description of item"
+// @Param 				ListInfo			body		model.UpdateItemInput	true	"Title, description, checked flag, quantity, unit, category or prices of item"
+// @Param 				If-Match			header		string	false					"ETag of the item version being updated"
 // @Success 			200 				{object} 	okResponse						"Object id"
 // @Failure				401					{object}	errorResponse					"Unauthorized"
 // @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				404					{object}	errorResponse					"Item not found"
+// @Failure				412					{object}	errorResponse					"Item was modified"
 // @Failure				500					{object}	errorResponse					"Failed to update the item"
 // @Router 				/api/items/{ID} 	[put]
 func (h *Handler) updateItem(c *gin.Context) {
@@ -145,7 +198,17 @@ func (h *Handler) updateItem(c *gin.Context) {
 		return
 	}
 
-	if err = h.services.Item.Update(userId, id, input); err != nil {
+	version, ok := ifMatchVersion(c, h.itemVersion(userId, id))
+	if !ok {
+		return
+	}
+
+	err = h.services.Item.Update(userId, id, input, version)
+	if errors.Is(err, model.ErrVersionMismatch) {
+		newErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -160,9 +223,12 @@ func (h *Handler) updateItem(c *gin.Context) {
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true						"ID of item"
+// @Param 				If-Match			header		string	false					"ETag of the item version being deleted"
 // @Success 			200 				{object} 	okResponse						"Object id"
 // @Failure				401					{object}	errorResponse					"Unauthorized"
 // @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				404					{object}	errorResponse					"Item not found"
+// @Failure				412					{object}	errorResponse					"Item was modified"
 // @Failure				500					{object}	errorResponse					"Failed to delete the item"
 // @Router 				/api/items/{ID} 	[delete]
 func (h *Handler) deleteItem(c *gin.Context) {
@@ -178,7 +244,118 @@ func (h *Handler) deleteItem(c *gin.Context) {
 		return
 	}
 
-	err = h.services.Item.Delete(userId, id)
+	version, ok := ifMatchVersion(c, h.itemVersion(userId, id))
+	if !ok {
+		return
+	}
+
+	err = h.services.Item.Delete(userId, id, version)
+	if errors.Is(err, model.ErrVersionMismatch) {
+		newErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Set item position
+// @Description 		Places shopping item between two neighbour items of its list and returns its new position
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of item"
+// @Param 				Neighbours			body		model.ItemPositionInput		true	"IDs of items right before and right after the new position"
+// @Param 				If-Match			header		string	false					"ETag of the item version being moved"
+// @Success 			200 				{object} 	positionResponse					"Object id and position"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				404					{object}	errorResponse						"Item not found"
+// @Failure				412					{object}	errorResponse						"Item was modified"
+// @Failure				500					{object}	errorResponse						"Failed to set the item position"
+// @Router 				/api/items/{ID}/position 	[put]
+func (h *Handler) setItemPosition(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	var input model.ItemPositionInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(c, h.itemVersion(userId, id))
+	if !ok {
+		return
+	}
+
+	position, err := h.services.Item.SetPosition(userId, id, input, version)
+	if errors.Is(err, model.ErrVersionMismatch) {
+		newErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, positionResponse{Id: id, Position: position})
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Move item
+// @Description 		Moves shopping item into another list keeping its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of item"
+// @Param 				Target				body		model.TransferItemInput		true	"ID of target list"
+// @Success 			200 				{object} 	okResponse							"Object id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				409					{object}	errorResponse						"Item has a price in another currency"
+// @Failure				500					{object}	errorResponse						"Failed to move the item"
+// @Router 				/api/items/{ID}/move 	[post]
+func (h *Handler) moveItem(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	var input model.TransferItemInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	err = h.services.Item.Move(userId, input.ListId, []int{id})
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -186,3 +363,219 @@ func (h *Handler) deleteItem(c *gin.Context) {
 
 	c.JSON(http.StatusOK, okResponse{Id: id})
 }
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Copy item
+// @Description 		Adds an unchecked copy of shopping item to a list and returns its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of item"
+// @Param 				Target				body		model.TransferItemInput		true	"ID of target list"
+// @Success 			200 				{object} 	okResponse							"Object id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				409					{object}	errorResponse						"Item has a price in another currency"
+// @Failure				500					{object}	errorResponse						"Failed to copy the item"
+// @Router 				/api/items/{ID}/copy 	[post]
+func (h *Handler) copyItem(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	var input model.TransferItemInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	ids, err := h.services.Item.Copy(userId, input.ListId, []int{id})
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: ids[0]})
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Move items
+// @Description 		Moves shopping items into another list keeping their IDs
+// @Accept  			json
+// @Produce  			json
+// @Param 				Transfer			body		model.TransferItemsInput	true	"ID of target list and IDs of items"
+// @Success 			200 				{object} 	okResponse							"Target list id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				409					{object}	errorResponse						"Items have prices in another currency"
+// @Failure				500					{object}	errorResponse						"Failed to move items"
+// @Router 				/api/items/move 	[post]
+func (h *Handler) moveItems(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.TransferItemsInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.Item.Move(userId, input.ListId, input.ItemIds)
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: input.ListId})
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Copy items
+// @Description 		Adds unchecked copies of shopping items to a list and returns their IDs in the order of passed items
+// @Accept  			json
+// @Produce  			json
+// @Param 				Transfer			body		model.TransferItemsInput	true	"ID of target list and IDs of items"
+// @Success 			200 				{object} 	idsResponse							"IDs of copies"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				409					{object}	errorResponse						"Items have prices in another currency"
+// @Failure				500					{object}	errorResponse						"Failed to copy items"
+// @Router 				/api/items/copy 	[post]
+func (h *Handler) copyItems(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.TransferItemsInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ids, err := h.services.Item.Copy(userId, input.ListId, input.ItemIds)
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, idsResponse{Ids: ids})
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Bulk item operations
+// @Description 		Executes operations over items of the list in one transaction: checkAll, uncheckAll, deleteChecked, delete and update by IDs, create
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of list"
+// @Param 				Operations			body		model.BulkItemsInput	true	"Operations executed in order"
+// @Success 			200 				{array} 	model.BulkItemResult			"Outcome for every item"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to execute operations"
+// @Router 				/api/lists/{ID}/items/bulk 	[post]
+func (h *Handler) bulkItems(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	var input model.BulkItemsInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.services.Item.Bulk(userId, listId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Quick add items
+// @Description 		Creates items parsed from free text in English or Russian with quantities, units and notes in parentheses
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true					"ID of list"
+// @Param 				Text				body		model.QuickAddInput	true	"Items separated by new lines, commas or semicolons"
+// @Success 			200 				{object} 	model.QuickAddResult		"Created items, unrecognized entries and budget warning"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to create items"
+// @Router 				/api/lists/{ID}/items/quick 	[post]
+func (h *Handler) quickAddItems(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	var input model.QuickAddInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	result, err := h.services.Item.QuickAdd(userId, listId, input.Text)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
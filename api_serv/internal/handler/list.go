@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -13,7 +14,7 @@ import (
 // @Description 		Creates a new shopping list and returns its ID
 // @Accept  			json
 // @Produce  			json
-// @Param 				ListInfo			body		model.ShoppingList	true	"Title and description of list"
+// @Param 				ListInfo			body		model.ShoppingList	true	"Title, description, budget and currency of list"
 // @Success 			200 				{object} 	okResponse			 		"Object id"
 // @Failure				401					{object}	errorResponse				"Unauthorized"
 // @Failure				400					{object}	errorResponse				"Invalid params"
@@ -42,11 +43,13 @@ func (h *Handler) createList(c *gin.Context) {
 // @Tags 				Api List
 // @Security 			JWTAuth
 // @Summary 			Get all lists
-// @Description 		Returns a collection of shopping lists
+// @Description 		Returns a collection of shopping lists, pinned first, then in user-defined order
 // @Accept				json
 // @Produce  			json
+// @Param 				archived			query		string	false			"true, false or all; archived lists are hidden by default"
 // @Success 			200 				{array} 	model.ShoppingList		"Collection of shopping lists"
 // @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
 // @Failure				500					{object}	errorResponse			"Failed to get lists"
 // @Router 				/api/lists 			[get]
 func (h *Handler) getAllLists(c *gin.Context) {
@@ -55,7 +58,18 @@ func (h *Handler) getAllLists(c *gin.Context) {
 		return
 	}
 
-	lists, err := h.services.List.GetAll(userId)
+	var archived *bool
+	switch filter := c.DefaultQuery("archived", "false"); filter {
+	case "all":
+	case "true", "false":
+		value := filter == "true"
+		archived = &value
+	default:
+		newErrorResponse(c, http.StatusBadRequest, "invalid archived param")
+		return
+	}
+
+	lists, err := h.services.List.GetAll(userId, archived)
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -67,11 +81,13 @@ func (h *Handler) getAllLists(c *gin.Context) {
 // @Tags 				Api List
 // @Security 			JWTAuth
 // @Summary 			Get list by id
-// @Description 		Returns shopping list by ID
+// @Description 		Returns shopping list by ID with the price totals of its items and the remaining budget
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true				"ID of list"
+// @Param 				If-None-Match		header		string	false			"ETag of the cached list with its totals"
 // @Success 			200 				{object} 	model.ShoppingList		"Shopping list object"
+// @Success 			304 				"Cached list is up to date"
 // @Failure				401					{object}	errorResponse			"Unauthorized"
 // @Failure				400					{object}	errorResponse			"Invalid params"
 // @Failure				500					{object}	errorResponse			"Failed to get the list"
@@ -94,6 +110,11 @@ func (h *Handler) getListById(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, listETag(list)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, list)
 }
 
@@ -104,10 +125,14 @@ func (h *Handler) getListById(c *gin.Context) {
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true						"ID of list"
-// @Param 				ListInfo			body		model.UpdateListInput	true	"Title or/and description of list"
+// @Param 				ListInfo			body		model.UpdateListInput	true	"Title, description, pinned flag, store, budget or currency of list"
+// @Param 				If-Match			header		string	false					"ETag of the list version being updated"
 // @Success 			200 				{object} 	okResponse						"Object id"
 // @Failure				401					{object}	errorResponse					"Unauthorized"
 // @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				404					{object}	errorResponse					"List not found"
+// @Failure				409					{object}	errorResponse					"List items have prices"
+// @Failure				412					{object}	errorResponse					"List was modified"
 // @Failure				500					{object}	errorResponse					"Failed to update the list"
 // @Router 				/api/lists/{ID} 	[put]
 func (h *Handler) updateList(c *gin.Context) {
@@ -128,7 +153,20 @@ func (h *Handler) updateList(c *gin.Context) {
 		return
 	}
 
-	err = h.services.List.Update(userId, id, input)
+	version, ok := ifMatchVersion(c, h.listVersion(userId, id))
+	if !ok {
+		return
+	}
+
+	err = h.services.List.Update(userId, id, input, version)
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, model.ErrVersionMismatch) {
+		newErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -144,9 +182,12 @@ func (h *Handler) updateList(c *gin.Context) {
 // @Accept  			json
 // @Produce  			json
 // @Param 				ID					path		int	true				"ID of list"
+// @Param 				If-Match			header		string	false			"ETag of the list version being deleted"
 // @Success 			200 				{object} 	okResponse				"Object id"
 // @Failure				401					{object}	errorResponse			"Unauthorized"
 // @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				404					{object}	errorResponse			"List not found"
+// @Failure				412					{object}	errorResponse			"List was modified"
 // @Failure				500					{object}	errorResponse			"Failed to delete the list"
 // @Router 				/api/lists/{ID} 	[delete]
 func (h *Handler) deleteList(c *gin.Context) {
@@ -161,7 +202,16 @@ func (h *Handler) deleteList(c *gin.Context) {
 		return
 	}
 
-	err = h.services.List.Delete(userId, id)
+	version, ok := ifMatchVersion(c, h.listVersion(userId, id))
+	if !ok {
+		return
+	}
+
+	err = h.services.List.Delete(userId, id, version)
+	if errors.Is(err, model.ErrVersionMismatch) {
+		newErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -169,3 +219,224 @@ func (h *Handler) deleteList(c *gin.Context) {
 
 	c.JSON(http.StatusOK, okResponse{Id: id})
 }
+
+// @Tags 				Api List
+// @Security 			JWTAuth
+// @Summary 			Archive list
+// @Description 		Hides shopping list from the default collection of lists
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of list"
+// @Success 			200 				{object} 	okResponse				"Object id"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to archive the list"
+// @Router 				/api/lists/{ID}/archive 	[post]
+func (h *Handler) archiveList(c *gin.Context) {
+	h.setListArchived(c, true)
+}
+
+// @Tags 				Api List
+// @Security 			JWTAuth
+// @Summary 			Unarchive list
+// @Description 		Returns archived shopping list to the default collection of lists
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of list"
+// @Success 			200 				{object} 	okResponse				"Object id"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to unarchive the list"
+// @Router 				/api/lists/{ID}/unarchive 	[post]
+func (h *Handler) unarchiveList(c *gin.Context) {
+	h.setListArchived(c, false)
+}
+
+func (h *Handler) setListArchived(c *gin.Context, archived bool) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	err = h.services.List.SetArchived(userId, id, archived)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api List
+// @Security 			JWTAuth
+// @Summary 			Reorder lists
+// @Description 		Sets the order of shopping lists to the order of passed IDs
+// @Accept  			json
+// @Produce  			json
+// @Param 				Order				body		model.ListOrderInput	true	"IDs of lists in the new order"
+// @Success 			200 				{object} 	okResponse						"Empty object"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to reorder lists"
+// @Router 				/api/lists/order 	[put]
+func (h *Handler) reorderLists(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.ListOrderInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.List.Reorder(userId, input.Ids)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{})
+}
+
+// @Tags 				Api List
+// @Security 			JWTAuth
+// @Summary 			Duplicate list
+// @Description 		Creates a copy of shopping list with its items and returns its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of list"
+// @Param 				ListInfo			body		model.DuplicateListInput	false	"Title of the copy and whether to copy only unchecked items"
+// @Success 			200 				{object} 	okResponse							"Object id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				500					{object}	errorResponse						"Failed to duplicate the list"
+// @Router 				/api/lists/{ID}/duplicate 	[post]
+func (h *Handler) duplicateList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.DuplicateListInput
+	if c.Request.ContentLength != 0 {
+		if err = c.BindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+			return
+		}
+	}
+
+	listId, err := h.services.List.Duplicate(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: listId})
+}
+
+// @Tags 				Api List
+// @Security 			JWTAuth
+// @Summary 			Merge lists
+// @Description 		Moves items of the passed lists into the target list and deletes them, items with the same title are consolidated
+// @Accept  			json
+// @Produce  			json
+// @Param 				Merge				body		model.MergeListsInput	true	"ID of target list and IDs of merged lists"
+// @Success 			200 				{object} 	okResponse						"Target list id"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				409					{object}	errorResponse					"Items have prices in another currency"
+// @Failure				500					{object}	errorResponse					"Failed to merge lists"
+// @Router 				/api/lists/merge 	[post]
+func (h *Handler) mergeLists(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.MergeListsInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.List.Merge(userId, input)
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		newErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: input.TargetId})
+}
+
+// @Tags 				Api List
+// @Security 			JWTAuth
+// @Summary 			Split list
+// @Description 		Moves the selected items of shopping list into a new list and returns its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of list"
+// @Param 				Split				body		model.SplitListInput	true	"Title of the new list and IDs of moved items"
+// @Success 			200 				{object} 	okResponse						"Object id"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to split the list"
+// @Router 				/api/lists/{ID}/split 	[post]
+func (h *Handler) splitList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.SplitListInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	listId, err := h.services.List.Split(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: listId})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	etagHeader        = "ETag"
+	ifMatchHeader     = "If-Match"
+	ifNoneMatchHeader = "If-None-Match"
+)
+
+func formatETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// listETag returns the entity tag of the list with its totals: the version followed by the totals
+// after dots, so that changes of the items change the tag while If-Match still checks the version.
+func listETag(list model.ShoppingList) string {
+	if list.Totals == nil {
+		return formatETag(list.Version)
+	}
+
+	return fmt.Sprintf(`"%d.%d.%d.%d"`, list.Version, list.Totals.Estimated, list.Totals.Spent, list.Totals.Pending)
+}
+
+// parseETag returns the version encoded in an entity tag or -1 if the tag was not issued by this server.
+func parseETag(tag string) int {
+	tag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "W/"), `"`)
+	tag, _, _ = strings.Cut(tag, ".")
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return -1
+	}
+
+	return version
+}
+
+// ifMatchVersion returns the version required by the If-Match header, 0 if the request is unconditional.
+// With the header set current is asked for the version of the object: a missing object is answered
+// with 404 and a list of tags without the current version with 412, then false is returned.
+func ifMatchVersion(c *gin.Context, current func() (int, error)) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader(ifMatchHeader))
+	if header == "" {
+		return 0, true
+	}
+
+	version, err := current()
+	if errors.Is(err, sql.ErrNoRows) {
+		newErrorResponse(c, http.StatusNotFound, "not found")
+		return 0, false
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return 0, false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return 0, true
+		}
+		// If-Match uses the strong comparison, weak tags never match
+		if !strings.HasPrefix(tag, "W/") && parseETag(tag) == version {
+			return version, true
+		}
+	}
+
+	newErrorResponse(c, http.StatusPreconditionFailed, model.ErrVersionMismatch.Error())
+	return 0, false
+}
+
+func (h *Handler) listVersion(userId, id int) func() (int, error) {
+	return func() (int, error) {
+		list, err := h.services.List.GetById(userId, id)
+		return list.Version, err
+	}
+}
+
+func (h *Handler) itemVersion(userId, id int) func() (int, error) {
+	return func() (int, error) {
+		item, err := h.services.Item.GetById(userId, id)
+		return item.Version, err
+	}
+}
+
+// notModified sets the ETag header and reports whether If-None-Match already holds the current tag.
+func notModified(c *gin.Context, etag string) bool {
+	c.Header(etagHeader, etag)
+
+	header := c.GetHeader(ifNoneMatchHeader)
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		// If-None-Match uses the weak comparison
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
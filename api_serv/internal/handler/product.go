@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Product
+// @Security 			JWTAuth
+// @Summary 			Get all products
+// @Description 		Returns a collection of products the user has recorded prices of
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	model.Product			"Collection of products"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				500					{object}	errorResponse			"Failed to get products"
+// @Router 				/api/products 		[get]
+func (h *Handler) getAllProducts(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	products, err := h.services.Product.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// @Tags 				Api Product
+// @Security 			JWTAuth
+// @Summary 			Get product prices
+// @Description 		Returns the price history of the product, the latest unit prices in every store from the cheapest and the price trend
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true					"ID of product"
+// @Success 			200 				{object} 	model.ProductPrices			"Price history and comparison"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to get the prices"
+// @Router 				/api/products/{ID}/prices 	[get]
+func (h *Handler) getProductPrices(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	prices, err := h.services.Product.GetPrices(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/csv"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const reportDateLayout = "2006-01-02"
+
+// @Tags 				Api Report
+// @Security 			JWTAuth
+// @Summary 			Get spending report
+// @Description 		Returns the spending on purchases of shopping trips grouped by month, ISO week, category, store or member who did the shopping and by currency. Totals are in minor units, in CSV with two decimal places
+// @Accept				json
+// @Produce  			json
+// @Produce  			text/csv
+// @Param 				group				query		string	false				"month, week, category, store or member, month by default"
+// @Param 				from				query		string	false				"first day of the report, YYYY-MM-DD"
+// @Param 				to					query		string	false				"last day of the report, YYYY-MM-DD"
+// @Param 				format				query		string	false				"csv for a CSV file"
+// @Success 			200 				{array} 	model.SpendingRow		"Spending by group"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to get the report"
+// @Router 				/api/reports/spending 	[get]
+func (h *Handler) getSpendingReport(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	filter := model.SpendingFilter{Group: c.DefaultQuery("group", model.ReportGroupMonth)}
+	if filter.From, err = parseReportDate(c.Query("from")); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid from param")
+		return
+	}
+	if filter.To, err = parseReportDate(c.Query("to")); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid to param")
+		return
+	}
+
+	format := c.Query("format")
+	if format != "" && format != "json" && format != "csv" {
+		newErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+
+	if err = filter.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := h.services.Report.Spending(userId, filter)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format != "csv" {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="spending-by-`+filter.Group+`.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{filter.Group, "label", "currency", "total", "purchases", "trips"})
+	for _, row := range rows {
+		w.Write([]string{csvText(row.Key), csvText(row.Label), csvText(row.Currency), model.FormatAmount(row.Total),
+			strconv.Itoa(row.Purchases), strconv.Itoa(row.Trips)})
+	}
+	w.Flush()
+}
+
+// csvText escapes a text cell that spreadsheets would take for a formula, like a category
+// or a store titled "=HYPERLINK(...)", by prefixing it with an apostrophe.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// parseReportDate parses a day of the report, an empty value is nil.
+func parseReportDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(reportDateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
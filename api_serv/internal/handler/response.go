@@ -9,6 +9,20 @@ type okResponse struct {
 	Id int `json:"id"`
 }
 
+type idsResponse struct {
+	Ids []int `json:"ids"`
+}
+
+type itemResponse struct {
+	Id      int    `json:"id"`
+	Warning string `json:"warning,omitempty"`
+}
+
+type positionResponse struct {
+	Id       int    `json:"id"`
+	Position string `json:"position"`
+}
+
 type tokenResponse struct {
 	Token string `json:"token"`
 }
@@ -21,3 +35,14 @@ func newErrorResponse(c *gin.Context, statusCode int, message string) {
 	logrus.Error(message)
 	c.AbortWithStatusJSON(statusCode, errorResponse{message})
 }
+
+// budgetWarning returns the budget warning of the list an item has been added to. The item is
+// saved already, so a failure is logged and the response goes without the warning.
+func (h *Handler) budgetWarning(userId, listId int) string {
+	warning, err := h.services.List.BudgetWarning(userId, listId)
+	if err != nil {
+		logrus.Errorf("failed to check the budget of list %d: %s", listId, err.Error())
+	}
+
+	return warning
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+const defaultPreviewCount = 5
+
+// @Tags 				Api Schedule
+// @Security 			JWTAuth
+// @Summary 			Create schedule
+// @Description 		Creates a recurring schedule that instantiates a template or adds its items to a list, returns its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ScheduleInfo		body		model.Schedule		true	"Template, optional target list, RRULE, start and time zone"
+// @Success 			200 				{object} 	okResponse			 		"Object id"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to create a schedule"
+// @Router 				/api/schedules 		[post]
+func (h *Handler) createSchedule(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.Schedule
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	id, err := h.services.Schedule.Create(userId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Schedule
+// @Security 			JWTAuth
+// @Summary 			Get all schedules
+// @Description 		Returns a collection of recurring schedules
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	model.Schedule			"Collection of schedules"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				500					{object}	errorResponse			"Failed to get schedules"
+// @Router 				/api/schedules 		[get]
+func (h *Handler) getAllSchedules(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	schedules, err := h.services.Schedule.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// @Tags 				Api Schedule
+// @Security 			JWTAuth
+// @Summary 			Get schedule by id
+// @Description 		Returns recurring schedule by ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of schedule"
+// @Success 			200 				{object} 	model.Schedule			"Schedule object"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to get the schedule"
+// @Router 				/api/schedules/{ID} 	[get]
+func (h *Handler) getScheduleById(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	schedule, err := h.services.Schedule.GetById(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// @Tags 				Api Schedule
+// @Security 			JWTAuth
+// @Summary 			Update schedule by id
+// @Description 		Updates recurring schedule by ID and recalculates its next run, listId 0 detaches the target list
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of schedule"
+// @Param 				ScheduleInfo		body		model.UpdateScheduleInput	true	"Changed fields of schedule"
+// @Success 			200 				{object} 	okResponse							"Object id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				500					{object}	errorResponse						"Failed to update the schedule"
+// @Router 				/api/schedules/{ID} 	[put]
+func (h *Handler) updateSchedule(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.UpdateScheduleInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	err = h.services.Schedule.Update(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Schedule
+// @Security 			JWTAuth
+// @Summary 			Delete schedule by id
+// @Description 		Deletes recurring schedule by ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of schedule"
+// @Success 			200 				{object} 	okResponse				"Object id"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to delete the schedule"
+// @Router 				/api/schedules/{ID} 	[delete]
+func (h *Handler) deleteSchedule(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	err = h.services.Schedule.Delete(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Schedule
+// @Security 			JWTAuth
+// @Summary 			Preview schedule
+// @Description 		Returns upcoming occurrences of recurring schedule
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of schedule"
+// @Param 				count				query		int	false				"Number of occurrences, 5 by default"
+// @Success 			200 				{array} 	string					"Occurrence times"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to preview the schedule"
+// @Router 				/api/schedules/{ID}/preview 	[get]
+func (h *Handler) previewSchedule(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	count := defaultPreviewCount
+	if value := c.Query("count"); value != "" {
+		count, err = strconv.Atoi(value)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid count param")
+			return
+		}
+	}
+
+	occurrences, err := h.services.Schedule.Preview(userId, id, count)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrences)
+}
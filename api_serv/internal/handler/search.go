@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Search
+// @Security 			JWTAuth
+// @Summary 			Search lists and items
+// @Description 		Searches titles and descriptions of the lists of the user and their items by words in Russian and English, titles also match with typos. Results are ranked, titles weigh more than descriptions, and the matching words of HTML escaped snippets are marked with <mark> tags, list titles are HTML escaped too
+// @Accept				json
+// @Produce  			json
+// @Param 				q					query		string	true				"Search query, quoted phrases, or and -word are supported"
+// @Param 				limit				query		int		false				"Number of results on the page, 20 by default and 100 at most"
+// @Param 				offset				query		int		false				"Number of results to skip"
+// @Success 			200 				{object} 	model.SearchPage		"Page of results"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to search"
+// @Router 				/api/search 		[get]
+func (h *Handler) search(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	filter := model.SearchFilter{Query: c.Query("q"), Limit: model.DefaultSearchLimit}
+	if param := c.Query("limit"); param != "" {
+		if filter.Limit, err = strconv.Atoi(param); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid limit param")
+			return
+		}
+	}
+	if param := c.Query("offset"); param != "" {
+		if filter.Offset, err = strconv.Atoi(param); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid offset param")
+			return
+		}
+	}
+
+	if err = filter.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.services.Search.Search(userId, filter)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Snapshot
+// @Security 			JWTAuth
+// @Summary 			Create snapshot
+// @Description 		Saves the current state of the list with its items and returns snapshot ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true					"ID of list"
+// @Success 			200 				{object} 	okResponse			 		"Object id"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to create snapshot"
+// @Router 				/api/lists/{ID}/snapshots 		[post]
+func (h *Handler) createSnapshot(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	id, err := h.services.Snapshot.Create(userId, listId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Snapshot
+// @Security 			JWTAuth
+// @Summary 			Get all snapshots of the list
+// @Description 		Returns a collection of list snapshots without items, newest first
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true					"ID of list"
+// @Success 			200 				{array} 	model.ListSnapshot			"Collection of snapshots"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to get snapshots"
+// @Router 				/api/lists/{ID}/snapshots 		[get]
+func (h *Handler) getAllSnapshots(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	snapshots, err := h.services.Snapshot.GetAll(userId, listId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// @Tags 				Api Snapshot
+// @Security 			JWTAuth
+// @Summary 			Get snapshot by id
+// @Description 		Returns snapshot by ID with the items it contains
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of snapshot"
+// @Success 			200 				{object} 	model.ListSnapshot				"Snapshot object"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to get the snapshot"
+// @Router 				/api/snapshots/{ID} 	[get]
+func (h *Handler) getSnapshotById(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid snapshot id param")
+		return
+	}
+
+	snapshot, err := h.services.Snapshot.GetById(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// @Tags 				Api Snapshot
+// @Security 			JWTAuth
+// @Summary 			Diff snapshot
+// @Description 		Returns added, removed and changed items between the snapshot and another snapshot or the current list
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of snapshot"
+// @Param 				to					query		int	false						"ID of snapshot to compare with, current list state if omitted"
+// @Success 			200 				{object} 	model.ListDiff					"Diff object"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to diff the snapshot"
+// @Router 				/api/snapshots/{ID}/diff 	[get]
+func (h *Handler) diffSnapshot(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid snapshot id param")
+		return
+	}
+
+	toId := 0
+	if to := c.Query("to"); to != "" {
+		toId, err = strconv.Atoi(to)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid to param")
+			return
+		}
+	}
+
+	diff, err := h.services.Snapshot.Diff(userId, id, toId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Tags 				Api Snapshot
+// @Security 			JWTAuth
+// @Summary 			Restore list from snapshot
+// @Description 		Replaces list title, description and items with the snapshot content. Items moved to other lists since the snapshot are moved back. Returns list ID and IDs of moved back items
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of snapshot"
+// @Success 			200 				{object} 	model.SnapshotRestore			"Object id and moved back items"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to restore the list"
+// @Router 				/api/snapshots/{ID}/restore 	[post]
+func (h *Handler) restoreSnapshot(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid snapshot id param")
+		return
+	}
+
+	restore, err := h.services.Snapshot.Restore(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, restore)
+}
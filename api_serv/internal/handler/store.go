@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Store
+// @Security 			JWTAuth
+// @Summary 			Create store
+// @Description 		Creates a store profile with categories in aisle order and returns its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				StoreInfo			body		model.Store			true	"Title and aisles of store"
+// @Success 			200 				{object} 	okResponse			 		"Object id"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to create a store"
+// @Router 				/api/stores 		[post]
+func (h *Handler) createStore(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.Store
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	id, err := h.services.Store.Create(userId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Store
+// @Security 			JWTAuth
+// @Summary 			Get all stores
+// @Description 		Returns a collection of store profiles
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	model.Store				"Collection of stores"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				500					{object}	errorResponse			"Failed to get stores"
+// @Router 				/api/stores 		[get]
+func (h *Handler) getAllStores(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	stores, err := h.services.Store.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, stores)
+}
+
+// @Tags 				Api Store
+// @Security 			JWTAuth
+// @Summary 			Get store by id
+// @Description 		Returns store profile by ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of store"
+// @Success 			200 				{object} 	model.Store				"Store object"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to get the store"
+// @Router 				/api/stores/{ID} 	[get]
+func (h *Handler) getStoreById(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	store, err := h.services.Store.GetById(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+// @Tags 				Api Store
+// @Security 			JWTAuth
+// @Summary 			Update store by id
+// @Description 		Updates store profile by ID, passed aisles replace the current ones
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of store"
+// @Param 				StoreInfo			body		model.UpdateStoreInput		true	"Title or aisles of store"
+// @Success 			200 				{object} 	okResponse							"Object id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				500					{object}	errorResponse						"Failed to update the store"
+// @Router 				/api/stores/{ID} 	[put]
+func (h *Handler) updateStore(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.UpdateStoreInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.Store.Update(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Store
+// @Security 			JWTAuth
+// @Summary 			Delete store by id
+// @Description 		Deletes store profile by ID, lists assigned to it are left without a store
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of store"
+// @Success 			200 				{object} 	okResponse				"Object id"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to delete the store"
+// @Router 				/api/stores/{ID} 	[delete]
+func (h *Handler) deleteStore(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	err = h.services.Store.Delete(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
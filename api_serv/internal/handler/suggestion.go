@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Get suggestions
+// @Description 		Returns products that are probably due to be bought again according to the purchase history and are not on the list, the most confident first
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true					"ID of list"
+// @Success 			200 				{array} 	model.Suggestion			"Collection of suggestions"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to get suggestions"
+// @Router 				/api/lists/{ID}/suggestions 	[get]
+func (h *Handler) getSuggestions(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	suggestions, err := h.services.Suggestion.GetAll(userId, listId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// @Tags 				Api Item
+// @Security 			JWTAuth
+// @Summary 			Add suggestion
+// @Description 		Adds the suggested product to the list with the quantity and unit of its latest purchase and returns the item ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of list"
+// @Param 				Suggestion			body		model.AddSuggestionInput	true	"Title of suggested product"
+// @Success 			200 				{object} 	itemResponse					"Object id and budget warning"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to add the suggestion"
+// @Router 				/api/lists/{ID}/suggestions 	[post]
+func (h *Handler) addSuggestion(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	var input model.AddSuggestionInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	id, err := h.services.Suggestion.Add(userId, listId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, itemResponse{Id: id, Warning: h.budgetWarning(userId, listId)})
+}
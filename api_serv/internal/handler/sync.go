@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type syncPushResponse struct {
+	Results []model.SyncResult `json:"results"`
+}
+
+// @Tags 				Api Sync
+// @Security 			JWTAuth
+// @Summary 			Pull changes
+// @Description 		Returns lists, items and deletions made after the cursor together with the next cursor
+// @Accept  			json
+// @Produce  			json
+// @Param 				since				query		int	false					"Cursor returned by the previous pull, 0 for a full sync"
+// @Success 			200 				{object} 	model.SyncChanges			"Changes since cursor"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to get changes"
+// @Router 				/api/sync 			[get]
+func (h *Handler) pullChanges(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	since := 0
+	if cursor := c.Query("since"); cursor != "" {
+		since, err = strconv.Atoi(cursor)
+		if err != nil || since < 0 {
+			newErrorResponse(c, http.StatusBadRequest, "invalid since param")
+			return
+		}
+	}
+
+	changes, err := h.services.Sync.Pull(userId, since)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// @Tags 				Api Sync
+// @Security 			JWTAuth
+// @Summary 			Push changes
+// @Description 		Applies a batch of offline mutations and returns the result of each of them
+// @Accept  			json
+// @Produce  			json
+// @Param 				Mutations			body		model.SyncPushInput	true	"Client mutations in the order they were made"
+// @Success 			200 				{object} 	syncPushResponse			"Per-mutation results"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Router 				/api/sync 			[post]
+func (h *Handler) pushChanges(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.SyncPushInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	results := h.services.Sync.Push(userId, input.Mutations)
+
+	c.JSON(http.StatusOK, syncPushResponse{Results: results})
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Template
+// @Security 			JWTAuth
+// @Summary 			Create template
+// @Description 		Creates a list template from the passed items or from an existing list and returns its ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				TemplateInfo		body		model.CreateTemplateInput	true	"Title, description and items or ID of list to copy"
+// @Success 			200 				{object} 	okResponse			 		"Object id"
+// @Failure				401					{object}	errorResponse				"Unauthorized"
+// @Failure				400					{object}	errorResponse				"Invalid params"
+// @Failure				500					{object}	errorResponse				"Failed to create a template"
+// @Router 				/api/templates 		[post]
+func (h *Handler) createTemplate(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input model.CreateTemplateInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.Template.Create(userId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Template
+// @Security 			JWTAuth
+// @Summary 			Get all templates
+// @Description 		Returns a collection of list templates without items
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	model.ListTemplate		"Collection of templates"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				500					{object}	errorResponse			"Failed to get templates"
+// @Router 				/api/templates 		[get]
+func (h *Handler) getAllTemplates(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	templates, err := h.services.Template.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Tags 				Api Template
+// @Security 			JWTAuth
+// @Summary 			Get template by id
+// @Description 		Returns list template by ID with its items
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of template"
+// @Success 			200 				{object} 	model.ListTemplate		"Template object"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to get the template"
+// @Router 				/api/templates/{ID} 	[get]
+func (h *Handler) getTemplateById(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	template, err := h.services.Template.GetById(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Tags 				Api Template
+// @Security 			JWTAuth
+// @Summary 			Update template by id
+// @Description 		Updates list template by ID, passed items replace the current ones
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true							"ID of template"
+// @Param 				TemplateInfo		body		model.UpdateTemplateInput	true	"Title, description or items of template"
+// @Success 			200 				{object} 	okResponse							"Object id"
+// @Failure				401					{object}	errorResponse						"Unauthorized"
+// @Failure				400					{object}	errorResponse						"Invalid params"
+// @Failure				500					{object}	errorResponse						"Failed to update the template"
+// @Router 				/api/templates/{ID} 	[put]
+func (h *Handler) updateTemplate(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.UpdateTemplateInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+		return
+	}
+
+	err = h.services.Template.Update(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Template
+// @Security 			JWTAuth
+// @Summary 			Delete template by id
+// @Description 		Deletes list template by ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of template"
+// @Success 			200 				{object} 	okResponse				"Object id"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to delete the template"
+// @Router 				/api/templates/{ID} 	[delete]
+func (h *Handler) deleteTemplate(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	err = h.services.Template.Delete(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Template
+// @Security 			JWTAuth
+// @Summary 			Instantiate template
+// @Description 		Creates a new shopping list with all template items and returns the list ID
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true								"ID of template"
+// @Param 				ListInfo			body		model.InstantiateTemplateInput	false	"Title of the new list, template title if empty"
+// @Success 			200 				{object} 	okResponse								"Object id"
+// @Failure				401					{object}	errorResponse							"Unauthorized"
+// @Failure				400					{object}	errorResponse							"Invalid params"
+// @Failure				500					{object}	errorResponse							"Failed to create the list"
+// @Router 				/api/templates/{ID}/instantiate 	[post]
+func (h *Handler) instantiateTemplate(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.InstantiateTemplateInput
+	if c.Request.ContentLength != 0 {
+		if err = c.BindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+			return
+		}
+	}
+
+	listId, err := h.services.Template.Instantiate(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: listId})
+}
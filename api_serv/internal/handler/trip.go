@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Tags 				Api Trip
+// @Security 			JWTAuth
+// @Summary 			Start trip
+// @Description 		Starts a shopping trip with the list and returns its ID. Items checked off until the trip is finished are recorded as its purchases
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of list"
+// @Param 				TripInfo			body		model.StartTripInput	false	"Store of the trip, the store of the list if empty, and who does the shopping, the user if empty"
+// @Success 			200 				{object} 	okResponse						"Object id"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to start a trip"
+// @Router 				/api/lists/{ID}/trips 	[post]
+func (h *Handler) startTrip(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	var input model.StartTripInput
+	if c.Request.ContentLength != 0 {
+		if err = c.BindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+			return
+		}
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.Trip.Start(userId, listId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
+
+// @Tags 				Api Trip
+// @Security 			JWTAuth
+// @Summary 			Get all trips
+// @Description 		Returns the purchase history as a collection of trips with their totals, the latest first
+// @Accept				json
+// @Produce  			json
+// @Success 			200 				{array} 	model.Trip				"Collection of trips"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				500					{object}	errorResponse			"Failed to get trips"
+// @Router 				/api/trips 			[get]
+func (h *Handler) getAllTrips(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	trips, err := h.services.Trip.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, trips)
+}
+
+// @Tags 				Api Trip
+// @Security 			JWTAuth
+// @Summary 			Get trip by id
+// @Description 		Returns trip by ID with the items purchased during it
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true				"ID of trip"
+// @Success 			200 				{object} 	model.Trip				"Trip object"
+// @Failure				401					{object}	errorResponse			"Unauthorized"
+// @Failure				400					{object}	errorResponse			"Invalid params"
+// @Failure				500					{object}	errorResponse			"Failed to get the trip"
+// @Router 				/api/trips/{ID} 	[get]
+func (h *Handler) getTripById(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	trip, err := h.services.Trip.GetById(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// @Tags 				Api Trip
+// @Security 			JWTAuth
+// @Summary 			Finish trip
+// @Description 		Finishes the trip keeping its purchases in the history. Purchased items stay checked on the list, get unchecked or are removed
+// @Accept  			json
+// @Produce  			json
+// @Param 				ID					path		int	true						"ID of trip"
+// @Param 				TripInfo			body		model.FinishTripInput	false	"keep, uncheck or remove purchased items, keep if empty"
+// @Success 			200 				{object} 	okResponse						"Object id"
+// @Failure				401					{object}	errorResponse					"Unauthorized"
+// @Failure				400					{object}	errorResponse					"Invalid params"
+// @Failure				500					{object}	errorResponse					"Failed to finish the trip"
+// @Router 				/api/trips/{ID}/finish 	[post]
+func (h *Handler) finishTrip(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input model.FinishTripInput
+	if c.Request.ContentLength != 0 {
+		if err = c.BindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "check body: "+err.Error())
+			return
+		}
+	}
+
+	if err = input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.Trip.Finish(userId, id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, okResponse{Id: id})
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Completion is a title the user has used for items before, with the category, quantity,
+// unit and price of its latest item. Similarity is the trigram word similarity of the query
+// and Prefix reports whether the title or one of its words starts with the query.
+type Completion struct {
+	Title      string    `json:"title" db:"Last_title"`
+	Category   string    `json:"category" db:"Category"`
+	Quantity   float64   `json:"quantity" db:"Quantity"`
+	Unit       string    `json:"unit" db:"Unit"`
+	Price      int64     `json:"price" db:"Price"`
+	Currency   string    `json:"currency" db:"Currency"`
+	Uses       int       `json:"uses" db:"Uses"`
+	LastUsed   time.Time `json:"lastUsed" db:"Last_used"`
+	Similarity float64   `json:"-" db:"Similarity"`
+	Prefix     bool      `json:"-" db:"Prefix"`
+	Score      float64   `json:"score" db:"-"`
+}
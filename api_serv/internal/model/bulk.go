@@ -0,0 +1,84 @@
+package model
+
+import "errors"
+
+const (
+	BulkOpCheckAll      = "checkAll"
+	BulkOpUncheckAll    = "uncheckAll"
+	BulkOpDeleteChecked = "deleteChecked"
+	BulkOpDelete        = "delete"
+	BulkOpUpdate        = "update"
+	BulkOpCreate        = "create"
+)
+
+const (
+	BulkStatusApplied  = "applied"
+	BulkStatusNotFound = "notFound"
+)
+
+// BulkItemOperation is a single operation over items of a list. Delete and update
+// take the IDs of items, update applies Patch to all of them and create adds Items.
+type BulkItemOperation struct {
+	Op    string           `json:"op" binding:"required"`
+	Ids   []int            `json:"ids"`
+	Patch *UpdateItemInput `json:"patch"`
+	Items []ShoppingItem   `json:"items"`
+}
+
+// BulkItemsInput holds operations executed in order in one transaction.
+type BulkItemsInput struct {
+	Operations []BulkItemOperation `json:"operations" binding:"required,dive"`
+}
+
+func (i BulkItemsInput) Validate() error {
+	if len(i.Operations) == 0 {
+		return errors.New("bulk has no operations")
+	}
+
+	for _, op := range i.Operations {
+		switch op.Op {
+		case BulkOpCheckAll, BulkOpUncheckAll, BulkOpDeleteChecked:
+		case BulkOpDelete:
+			if len(op.Ids) == 0 {
+				return errors.New("delete has no items")
+			}
+		case BulkOpUpdate:
+			if len(op.Ids) == 0 {
+				return errors.New("update has no items")
+			}
+			if op.Patch == nil {
+				return errors.New("update has no patch")
+			}
+			if err := op.Patch.Validate(); err != nil {
+				return err
+			}
+		case BulkOpCreate:
+			if len(op.Items) == 0 {
+				return errors.New("create has no items")
+			}
+			for _, item := range op.Items {
+				if item.Title == "" {
+					return errors.New("item has no title")
+				}
+				if item.Quantity < 0 {
+					return errors.New("item quantity is negative")
+				}
+				if _, ok := LookupUnit(item.Unit); !ok {
+					return errors.New("unknown unit " + item.Unit)
+				}
+			}
+		default:
+			return errors.New("unknown operation " + op.Op)
+		}
+	}
+
+	return nil
+}
+
+// BulkItemResult reports the outcome of an operation for a single item. Items passed
+// by ID that are not in the list are reported as not found and skipped.
+type BulkItemResult struct {
+	Op     string `json:"op"`
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// CategoryOther groups items without a category.
+const CategoryOther = "other"
+
+// categories are the built-in categories in the order a typical store is walked through.
+var categories = []string{
+	"produce", "bakery", "meat", "fish", "dairy", "frozen", "pantry", "snacks",
+	"beverages", "baby", "pets", "household", "personal care", CategoryOther,
+}
+
+// Categories returns the built-in categories.
+func Categories() []string {
+	return append([]string(nil), categories...)
+}
+
+// CategoryRank orders the built-in categories by their position, then user-defined
+// ones and items without a category last.
+func CategoryRank(category string) int {
+	if category == CategoryOther {
+		return len(categories)
+	}
+	for i, c := range categories {
+		if c == category {
+			return i
+		}
+	}
+
+	return len(categories) - 1
+}
+
+// NormalizeCategory returns the category in lower case without surrounding spaces.
+func NormalizeCategory(category string) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if len([]rune(category)) > 30 {
+		return "", errors.New("category is longer than 30 characters")
+	}
+
+	return category, nil
+}
+
+// ItemCategoryGroup holds the items of a list that belong to one category.
+type ItemCategoryGroup struct {
+	Category string         `json:"category"`
+	Items    []ShoppingItem `json:"items"`
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	FieldTitle          = "title"
+	FieldDescription    = "description"
+	FieldChecked        = "checked"
+	FieldQuantity       = "quantity"
+	FieldUnit           = "unit"
+	FieldCategory       = "category"
+	FieldEstimatedPrice = "estimatedPrice"
+	FieldActualPrice    = "actualPrice"
+)
+
+// Timestamp is a hybrid logical clock reading: physical time in milliseconds, a logical
+// counter for events within the same millisecond and the node that produced the event.
+type Timestamp struct {
+	Wall    int64
+	Logical int
+	Node    string
+}
+
+func (t Timestamp) IsZero() bool {
+	return t.Wall == 0 && t.Logical == 0 && t.Node == ""
+}
+
+// Compare orders timestamps by wall time, then logical counter, then node, so any two
+// timestamps from different nodes are never equal.
+func (t Timestamp) Compare(o Timestamp) int {
+	switch {
+	case t.Wall != o.Wall:
+		if t.Wall < o.Wall {
+			return -1
+		}
+		return 1
+	case t.Logical != o.Logical:
+		if t.Logical < o.Logical {
+			return -1
+		}
+		return 1
+	}
+
+	return strings.Compare(t.Node, o.Node)
+}
+
+func (t Timestamp) String() string {
+	return fmt.Sprintf("%013d:%05d:%s", t.Wall, t.Logical, t.Node)
+}
+
+func ParseTimestamp(s string) (Timestamp, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return Timestamp{}, errors.New("invalid clock value " + s)
+	}
+
+	wall, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Timestamp{}, errors.New("invalid clock value " + s)
+	}
+	logical, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Timestamp{}, errors.New("invalid clock value " + s)
+	}
+
+	return Timestamp{Wall: wall, Logical: logical, Node: parts[2]}, nil
+}
+
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimestamp(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// FieldClocks holds the timestamp of the last write to every field of an item.
+type FieldClocks map[string]Timestamp
+
+// Value encodes clocks as a JSON string: lib/pq would send a byte slice as bytea.
+func (c FieldClocks) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *FieldClocks) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*c = FieldClocks{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into FieldClocks", src)
+	}
+
+	clocks := FieldClocks{}
+	if err := json.Unmarshal(data, &clocks); err != nil {
+		return err
+	}
+	*c = clocks
+	return nil
+}
+
+// MembershipTag is an add tag of the observed-remove set that keeps an item in its list.
+type MembershipTag struct {
+	Tag     string `json:"tag" db:"Tag"`
+	Removed bool   `json:"removed" db:"Removed"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "errors"
+
+// ErrVersionMismatch is returned when a conditional update or delete was made
+// against a version of the object that is no longer current.
+var ErrVersionMismatch = errors.New("object version does not match")
+
+// ErrCurrencyMismatch is returned when prices of items would change their currency:
+// the currency of a list with priced items is changed or priced items are moved to
+// a list in another currency.
+var ErrCurrencyMismatch = errors.New("items have prices in another currency")
@@ -2,29 +2,187 @@ package model
 
 import "errors"
 
+// UpdateListInput changes the list. StoreId 0 unassigns the list from its store, Budget 0 removes the budget.
 type UpdateListInput struct {
 	Title       *string `json:"title"`
 	Description *string `json:"description"`
+	Pinned      *bool   `json:"pinned"`
+	StoreId     *int    `json:"storeId"`
+	Budget      *int64  `json:"budget"`
+	Currency    *string `json:"currency"`
+	Archived    *bool   `json:"-"`
 }
 
 func (i *UpdateListInput) Validate() error {
-	if i.Title == nil && i.Description == nil {
+	if i.Title == nil && i.Description == nil && i.Pinned == nil && i.StoreId == nil && i.Budget == nil &&
+		i.Currency == nil && i.Archived == nil {
 		return errors.New("update structure has no values")
 	}
+	if i.Budget != nil && *i.Budget < 0 {
+		return errors.New("list budget must not be negative")
+	}
+	if i.Currency != nil {
+		if _, err := NormalizeCurrency(*i.Currency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type ListOrderInput struct {
+	Ids []int `json:"ids" binding:"required"`
+}
+
+func (i ListOrderInput) Validate() error {
+	if len(i.Ids) == 0 {
+		return errors.New("order has no lists")
+	}
+
+	seen := make(map[int]bool, len(i.Ids))
+	for _, id := range i.Ids {
+		if seen[id] {
+			return errors.New("order contains duplicate lists")
+		}
+		seen[id] = true
+	}
 
 	return nil
 }
 
 type UpdateItemInput struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Checked     *bool   `json:"checked"`
+	Title          *string     `json:"title"`
+	Description    *string     `json:"description"`
+	Checked        *bool       `json:"checked"`
+	Quantity       *float64    `json:"quantity"`
+	Unit           *string     `json:"unit"`
+	Category       *string     `json:"category"`
+	EstimatedPrice *int64      `json:"estimatedPrice"`
+	ActualPrice    *int64      `json:"actualPrice"`
+	Clocks         FieldClocks `json:"-"`
 }
 
 func (i UpdateItemInput) Validate() error {
-	if i.Title == nil && i.Description == nil && i.Checked == nil {
+	if i.Title == nil && i.Description == nil && i.Checked == nil && i.Quantity == nil && i.Unit == nil && i.Category == nil &&
+		i.EstimatedPrice == nil && i.ActualPrice == nil {
 		return errors.New("update structure has no values")
 	}
+	if i.Quantity != nil && *i.Quantity <= 0 {
+		return errors.New("item quantity must be positive")
+	}
+	if i.EstimatedPrice != nil && *i.EstimatedPrice < 0 || i.ActualPrice != nil && *i.ActualPrice < 0 {
+		return errors.New("item price must not be negative")
+	}
+	if i.Unit != nil {
+		if _, ok := LookupUnit(*i.Unit); !ok {
+			return errors.New("unknown unit " + *i.Unit)
+		}
+	}
+	if i.Category != nil {
+		if _, err := NormalizeCategory(*i.Category); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DuplicateListInput copies a list with its items. Title defaults to the title of the copied list.
+type DuplicateListInput struct {
+	Title         string `json:"title"`
+	UncheckedOnly bool   `json:"uncheckedOnly"`
+}
+
+// MergeListsInput moves the items of lists Ids into list TargetId and deletes the emptied lists.
+// Items with the same title and unit are consolidated into one with the summed quantity.
+type MergeListsInput struct {
+	TargetId int   `json:"targetId" binding:"required"`
+	Ids      []int `json:"ids" binding:"required"`
+}
+
+func (i MergeListsInput) Validate() error {
+	if len(i.Ids) == 0 {
+		return errors.New("merge has no lists")
+	}
+
+	seen := map[int]bool{i.TargetId: true}
+	for _, id := range i.Ids {
+		if seen[id] {
+			return errors.New("merge contains duplicate lists")
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// SplitListInput moves the items ItemIds into a new list with the given title.
+type SplitListInput struct {
+	Title   string `json:"title" binding:"required"`
+	ItemIds []int  `json:"itemIds" binding:"required"`
+}
+
+func (i SplitListInput) Validate() error {
+	if i.Title == "" {
+		return errors.New("list has no title")
+	}
+	if len(i.ItemIds) == 0 {
+		return errors.New("split has no items")
+	}
+
+	seen := make(map[int]bool, len(i.ItemIds))
+	for _, id := range i.ItemIds {
+		if seen[id] {
+			return errors.New("split contains duplicate items")
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// TransferItemInput moves or copies an item into the list ListId.
+type TransferItemInput struct {
+	ListId int `json:"listId" binding:"required"`
+}
+
+// TransferItemsInput moves or copies the items ItemIds into the list ListId.
+type TransferItemsInput struct {
+	ListId  int   `json:"listId" binding:"required"`
+	ItemIds []int `json:"itemIds" binding:"required"`
+}
+
+func (i TransferItemsInput) Validate() error {
+	if len(i.ItemIds) == 0 {
+		return errors.New("transfer has no items")
+	}
+
+	seen := make(map[int]bool, len(i.ItemIds))
+	for _, id := range i.ItemIds {
+		if seen[id] {
+			return errors.New("transfer contains duplicate items")
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// ItemPositionInput places the item between the neighbour items AfterId and BeforeId of
+// its list. One of them may be omitted, then the item is placed right after AfterId
+// or right before BeforeId.
+type ItemPositionInput struct {
+	AfterId  int `json:"afterId"`
+	BeforeId int `json:"beforeId"`
+}
+
+func (i ItemPositionInput) Validate() error {
+	if i.AfterId == 0 && i.BeforeId == 0 {
+		return errors.New("position has no neighbour items")
+	}
+	if i.AfterId == i.BeforeId {
+		return errors.New("neighbour items are the same")
+	}
 
 	return nil
 }
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency is the currency of lists created without one.
+const DefaultCurrency = "RUB"
+
+// ListTotals sums the prices of the items of a list in minor units of its currency.
+// An item without an estimated price counts with its actual price and the other way round.
+type ListTotals struct {
+	// Estimated is the expected cost of all items
+	Estimated int64 `json:"estimated" db:"Estimated"`
+	// Spent is the cost of the checked items
+	Spent int64 `json:"spent" db:"Spent"`
+	// Pending is the expected cost of the unchecked items
+	Pending int64 `json:"pending" db:"Pending"`
+	// Remaining is the budget left after buying the unchecked items, nil without a budget
+	Remaining  *int64 `json:"remaining,omitempty" db:"-"`
+	OverBudget bool   `json:"overBudget" db:"-"`
+}
+
+// ApplyBudget computes the remaining budget, a zero budget means the list has none.
+func (t *ListTotals) ApplyBudget(budget int64) {
+	if budget == 0 {
+		return
+	}
+
+	remaining := budget - t.Spent - t.Pending
+	t.Remaining = &remaining
+	t.OverBudget = remaining < 0
+}
+
+// NormalizeCurrency returns the ISO 4217 code of the currency in upper case.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", errors.New("invalid currency " + code)
+	}
+
+	return code, nil
+}
+
+// FormatPrice formats an amount in minor units with two decimal places and the currency code.
+func FormatPrice(amount int64, currency string) string {
+	return FormatAmount(amount) + " " + currency
+}
+
+// FormatAmount formats an amount in minor units with two decimal places.
+func FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+const (
+	TrendUnknown = "unknown"
+	TrendUp      = "up"
+	TrendDown    = "down"
+	TrendStable  = "stable"
+)
+
+// Product is a product the user has bought, identified by the normalized title of its items.
+type Product struct {
+	Id    int    `json:"id" db:"ID"`
+	Title string `json:"title" db:"Title"`
+}
+
+// PriceObservation is the actual price of a checked item in minor units of Currency. UnitPrice
+// is the price of the reference unit of its dimension, a kilogram, a litre, a piece or a pack.
+type PriceObservation struct {
+	Id         int       `json:"id" db:"ID"`
+	StoreId    *int      `json:"storeId" db:"Store_id"`
+	StoreTitle *string   `json:"storeTitle" db:"Store_title"`
+	Price      int64     `json:"price" db:"Price"`
+	Quantity   float64   `json:"quantity" db:"Quantity"`
+	Unit       string    `json:"unit" db:"Unit"`
+	Currency   string    `json:"currency" db:"Currency"`
+	UnitPrice  float64   `json:"unitPrice" db:"-"`
+	ObservedAt time.Time `json:"observedAt" db:"Observed_at"`
+}
+
+// StorePrice is the latest unit price of a product in a store.
+type StorePrice struct {
+	StoreId    int       `json:"storeId"`
+	StoreTitle string    `json:"storeTitle"`
+	UnitPrice  float64   `json:"unitPrice"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// ProductPrices is the price history of a product, newest first. Stores and the trend compare
+// the observations in the currency and unit dimension of the latest one, Stores go from the
+// cheapest. Change is the relative difference of the latest unit price to the average of the
+// previous ones.
+type ProductPrices struct {
+	Product  Product            `json:"product"`
+	History  []PriceObservation `json:"history"`
+	Unit     string             `json:"unit"`
+	Currency string             `json:"currency"`
+	Stores   []StorePrice       `json:"stores"`
+	Cheapest *StorePrice        `json:"cheapest,omitempty"`
+	Trend    string             `json:"trend"`
+	Change   float64            `json:"change"`
+}
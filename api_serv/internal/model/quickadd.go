@@ -0,0 +1,22 @@
+package model
+
+// QuickAddInput holds free text with one item per line or items separated by commas
+// or semicolons, e.g. "2kg apples, 3 x milk 1l; bread (wholegrain)".
+type QuickAddInput struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// QuickAddResult holds the created items, the entries of the text that were not recognized
+// and a warning when the list exceeds its budget.
+type QuickAddResult struct {
+	Items    []ShoppingItem `json:"items"`
+	Unparsed []string       `json:"unparsed"`
+	Warning  string         `json:"warning,omitempty"`
+}
+
+// ItemUpdate is an update of an item applied only if the item still has the version.
+type ItemUpdate struct {
+	Id      int
+	Version int
+	Input   UpdateItemInput
+}
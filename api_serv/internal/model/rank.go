@@ -0,0 +1,101 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// rankDigits are the digits of item positions in ascending byte order. Positions are
+// compared bytewise and never end with the lowest digit, so there is always a
+// position before any other one.
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrRankOrder is returned when the position after is not lower than the position before.
+var ErrRankOrder = errors.New("positions are not in order")
+
+// RankAfter returns a short position after the passed one, an empty position means
+// the list has no items. It matches item_rank_after that appends items in the database.
+func RankAfter(rank string) string {
+	for i := len(rank) - 1; i >= 0; i-- {
+		if rank[i] != rankDigits[len(rankDigits)-1] {
+			return rank[:i] + string(rankDigits[strings.IndexByte(rankDigits, rank[i])+1])
+		}
+	}
+
+	return rank + string(rankDigits[len(rankDigits)/2])
+}
+
+// RankBetween returns a position between after and before. An empty after is the start
+// of the list and an empty before is its end.
+func RankBetween(after, before string) (string, error) {
+	if !validRank(after) || !validRank(before) {
+		return "", errors.New("invalid position")
+	}
+	if before == "" {
+		return RankAfter(after), nil
+	}
+	if after >= before {
+		return "", ErrRankOrder
+	}
+
+	return rankMidpoint(after, before), nil
+}
+
+// rankMidpoint returns a position between a and b, where the empty b is the end of the list.
+func rankMidpoint(a, b string) string {
+	if b != "" {
+		// common prefix, a is padded with the lowest digit
+		n := 0
+		for n < len(b) && rankDigit(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankMidpoint(rankTail(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	return string(rankDigits[digitA]) + rankMidpoint(rankTail(a, 1), "")
+}
+
+func rankDigit(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+func rankTail(rank string, n int) string {
+	if n < len(rank) {
+		return rank[n:]
+	}
+	return ""
+}
+
+func validRank(rank string) bool {
+	if rank != "" && rank[len(rank)-1] == rankDigits[0] {
+		return false
+	}
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
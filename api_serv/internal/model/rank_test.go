@@ -0,0 +1,165 @@
+package model
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// itemRankAfter is item_rank_after of the schema with its 1-based substr and strpos.
+func itemRankAfter(rank string) string {
+	substr := func(s string, from, count int) string {
+		from--
+		if from >= len(s) || count <= 0 {
+			return ""
+		}
+		return s[from:min(from+count, len(s))]
+	}
+	strpos := func(s, sub string) int {
+		return strings.Index(s, sub) + 1
+	}
+
+	for i := len(rank); i >= 1; i-- {
+		if substr(rank, i, 1) != "z" {
+			return substr(rank, 1, i-1) + substr(rankDigits, strpos(rankDigits, substr(rank, i, 1))+1, 1)
+		}
+	}
+
+	return rank + "V"
+}
+
+func TestRankAfter(t *testing.T) {
+	tests := []struct {
+		rank string
+		want string
+	}{
+		{"", "V"},
+		{"V", "W"},
+		{"9", "A"},
+		{"Z", "a"},
+		{"y", "z"},
+		// the highest digit carries to the previous one or appends the middle digit
+		{"z", "zV"},
+		{"zz", "zzV"},
+		{"Az", "B"},
+		{"Azz", "B"},
+		{"yzz", "z"},
+		{"zVz", "zW"},
+		{"01", "02"},
+	}
+
+	for _, tt := range tests {
+		if got := RankAfter(tt.rank); got != tt.want {
+			t.Errorf("RankAfter(%q) = %q, want %q", tt.rank, got, tt.want)
+		}
+		if got := itemRankAfter(tt.rank); got != tt.want {
+			t.Errorf("item_rank_after(%q) = %q, want %q", tt.rank, got, tt.want)
+		}
+	}
+}
+
+func TestRankAfterMatchesDatabase(t *testing.T) {
+	// appending many items the way the database does
+	rank := ""
+	for i := 0; i < 5000; i++ {
+		next := RankAfter(rank)
+		if sql := itemRankAfter(rank); next != sql {
+			t.Fatalf("RankAfter(%q) = %q, item_rank_after returns %q", rank, next, sql)
+		}
+		if next <= rank || !validRank(next) {
+			t.Fatalf("RankAfter(%q) = %q is not a valid position after it", rank, next)
+		}
+		rank = next
+	}
+}
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		after  string
+		before string
+		want   string
+		err    bool
+	}{
+		{"", "", "V", false},
+		{"V", "", "W", false},
+		{"z", "", "zV", false},
+		// before the first position
+		{"", "V", "G", false},
+		{"", "1", "0V", false},
+		{"", "01", "00V", false},
+		// between
+		{"V", "X", "W", false},
+		{"V", "W", "VV", false},
+		{"y", "z", "yV", false},
+		{"A", "B5", "B", false},
+		{"A1", "B", "AW", false},
+		{"A", "A1", "A0V", false},
+		{"a", "aV", "aG", false},
+		{"zV", "zW", "zVV", false},
+
+		{"V", "V", "", true},
+		{"W", "V", "", true},
+		{"V0", "", "", true},
+		{"", "0", "", true},
+		{"V", "a-", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := RankBetween(tt.after, tt.before)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("RankBetween(%q, %q) = %q, %v, want %q", tt.after, tt.before, got, err, tt.want)
+		}
+	}
+
+	if _, err := RankBetween("W", "V"); !errors.Is(err, ErrRankOrder) {
+		t.Errorf("got error %v, want ErrRankOrder", err)
+	}
+}
+
+func TestRankBetweenKeepsOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name string
+		// at returns where to insert into the positions
+		at func(n int) int
+	}{
+		{"random", func(n int) int { return random.Intn(n + 1) }},
+		{"first", func(n int) int { return 0 }},
+		{"last", func(n int) int { return n }},
+		{"after the first", func(n int) int { return min(1, n) }},
+		{"before the last", func(n int) int { return max(n-1, 0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranks []string
+			for i := 0; i < 2000; i++ {
+				at := tt.at(len(ranks))
+				after, before := "", ""
+				if at > 0 {
+					after = ranks[at-1]
+				}
+				if at < len(ranks) {
+					before = ranks[at]
+				}
+
+				rank, err := RankBetween(after, before)
+				if err != nil {
+					t.Fatalf("RankBetween(%q, %q): %v", after, before, err)
+				}
+				if !validRank(rank) || rank <= after || before != "" && rank >= before {
+					t.Fatalf("RankBetween(%q, %q) = %q is not a valid position between them", after, before, rank)
+				}
+
+				ranks = append(ranks[:at], append([]string{rank}, ranks[at:]...)...)
+			}
+
+			if !sort.StringsAreSorted(ranks) {
+				t.Fatal("positions are not in order")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Groups of the spending report.
+const (
+	ReportGroupMonth    = "month"
+	ReportGroupWeek     = "week"
+	ReportGroupCategory = "category"
+	ReportGroupStore    = "store"
+	ReportGroupMember   = "member"
+)
+
+// SpendingFilter selects the purchases checked off from From until To, both days included,
+// and groups them by Group. Nil dates leave the range open.
+type SpendingFilter struct {
+	Group string
+	From  *time.Time
+	To    *time.Time
+}
+
+func (f SpendingFilter) Validate() error {
+	switch f.Group {
+	case ReportGroupMonth, ReportGroupWeek, ReportGroupCategory, ReportGroupStore, ReportGroupMember:
+	default:
+		return errors.New("unknown report group " + f.Group)
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return errors.New("report range ends before it starts")
+	}
+
+	return nil
+}
+
+// SpendingRow is the spending of a group in minor units of Currency. Key identifies the group:
+// a month like 2024-03, an ISO week like 2024-W09, a category, a store ID or the shopper of the trips.
+// Label is the store title and the key otherwise.
+type SpendingRow struct {
+	Key       string `json:"key" db:"Key"`
+	Label     string `json:"label" db:"Label"`
+	Currency  string `json:"currency" db:"Currency"`
+	Total     int64  `json:"total" db:"Total"`
+	Purchases int    `json:"purchases" db:"Purchases"`
+	Trips     int    `json:"trips" db:"Trips"`
+}
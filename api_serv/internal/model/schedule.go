@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Schedule regenerates lists from a template. Without ListId every occurrence creates
+// a new list, otherwise the template items are added to that list.
+// Rule is a subset of RFC 5545 RRULE, e.g. "FREQ=WEEKLY;BYDAY=SA".
+type Schedule struct {
+	Id         int        `json:"id" db:"ID"`
+	TemplateId int        `json:"templateId" db:"Template_id" binding:"required"`
+	ListId     *int       `json:"listId" db:"List_id"`
+	Title      string     `json:"title" db:"Title"`
+	Rule       string     `json:"rule" db:"Rule" binding:"required"`
+	Start      time.Time  `json:"start" db:"Start" binding:"required"`
+	Timezone   string     `json:"timezone" db:"Timezone"`
+	Enabled    bool       `json:"enabled" db:"Enabled"`
+	NextRun    *time.Time `json:"nextRun" db:"Next_run"`
+	LastRun    *time.Time `json:"lastRun" db:"Last_run"`
+	UserId     int        `json:"-" db:"User_id"`
+}
+
+type UpdateScheduleInput struct {
+	TemplateId *int       `json:"templateId"`
+	ListId     *int       `json:"listId"`
+	Title      *string    `json:"title"`
+	Rule       *string    `json:"rule"`
+	Start      *time.Time `json:"start"`
+	Timezone   *string    `json:"timezone"`
+	Enabled    *bool      `json:"enabled"`
+}
+
+func (i UpdateScheduleInput) Validate() error {
+	if i.TemplateId == nil && i.ListId == nil && i.Title == nil && i.Rule == nil &&
+		i.Start == nil && i.Timezone == nil && i.Enabled == nil {
+		return errors.New("update structure has no values")
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// Kinds of search results.
+const (
+	SearchKindList = "list"
+	SearchKindItem = "item"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// SearchFilter selects a page of Limit results after Offset ones that match Query.
+type SearchFilter struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+func (f SearchFilter) Validate() error {
+	if strings.TrimSpace(f.Query) == "" {
+		return errors.New("search query is empty")
+	}
+	if f.Limit < 1 || f.Limit > MaxSearchLimit {
+		return errors.New("search limit is out of range")
+	}
+	if f.Offset < 0 {
+		return errors.New("search offset is negative")
+	}
+
+	return nil
+}
+
+// SearchResult is a list or an item matching the query. Title and Description are HTML escaped
+// snippets with the matching words between <mark> and </mark>, ListId and ListTitle are the list
+// itself for lists and the list of the item for items. ListTitle is HTML escaped too.
+type SearchResult struct {
+	Kind        string  `json:"kind" db:"Kind"`
+	Id          int     `json:"id" db:"ID"`
+	ListId      int     `json:"listId" db:"List_id"`
+	ListTitle   string  `json:"listTitle" db:"List_title"`
+	Title       string  `json:"title" db:"Title"`
+	Description string  `json:"description" db:"Description"`
+	Rank        float64 `json:"rank" db:"Rank"`
+}
+
+// SearchPage is a page of search results, the best matching first, and the number of all of them.
+type SearchPage struct {
+	Total   int            `json:"total"`
+	Results []SearchResult `json:"results"`
+}
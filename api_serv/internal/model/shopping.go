@@ -1,9 +1,19 @@
 package model
 
+// ShoppingList is a list of the user. Budget is in minor units of Currency, 0 means no budget.
+// Totals are only computed for a single list.
 type ShoppingList struct {
-	Id          int    `json:"id" db:"ID"`
-	Title       string `json:"title" db:"Title" binding:"required"`
-	Description string `json:"description" db:"Description"`
+	Id          int         `json:"id" db:"ID"`
+	Title       string      `json:"title" db:"Title" binding:"required"`
+	Description string      `json:"description" db:"Description"`
+	Archived    bool        `json:"archived" db:"Archived"`
+	Pinned      bool        `json:"pinned" db:"Pinned"`
+	Position    int         `json:"position" db:"Position"`
+	StoreId     *int        `json:"storeId" db:"Store_id"`
+	Budget      int64       `json:"budget" db:"Budget"`
+	Currency    string      `json:"currency" db:"Currency"`
+	Totals      *ListTotals `json:"totals,omitempty" db:"-"`
+	Version     int         `json:"version" db:"Version"`
 }
 
 type UsersList struct {
@@ -12,11 +22,20 @@ type UsersList struct {
 	ListId int
 }
 
+// ShoppingItem is an item of a list. Prices are for the whole quantity in minor units
+// of the list currency, 0 means the price is unknown.
 type ShoppingItem struct {
-	Id          int    `json:"id" db:"ID"`
-	Title       string `json:"title" db:"Title"`
-	Description string `json:"description" db:"Description"`
-	Checked     bool   `json:"checked" db:"Checked"`
+	Id             int     `json:"id" db:"ID"`
+	Title          string  `json:"title" db:"Title"`
+	Description    string  `json:"description" db:"Description"`
+	Checked        bool    `json:"checked" db:"Checked"`
+	Quantity       float64 `json:"quantity" db:"Quantity"`
+	Unit           string  `json:"unit" db:"Unit"`
+	Category       string  `json:"category" db:"Category"`
+	EstimatedPrice int64   `json:"estimatedPrice" db:"Estimated_price"`
+	ActualPrice    int64   `json:"actualPrice" db:"Actual_price"`
+	Position       string  `json:"position" db:"Position"`
+	Version        int     `json:"version" db:"Version"`
 }
 
 type ListsItem struct {
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type ListSnapshot struct {
+	Id          int            `json:"id" db:"ID"`
+	ListId      int            `json:"listId" db:"List_id"`
+	Title       string         `json:"title" db:"Title"`
+	Description string         `json:"description" db:"Description"`
+	CreatedAt   time.Time      `json:"createdAt" db:"Created_at"`
+	Items       []ShoppingItem `json:"items,omitempty" db:"-"`
+}
+
+// SnapshotRestore is the outcome of a restore: the ID of the list and the IDs of the items
+// that had been moved to other lists of the user and were moved back.
+type SnapshotRestore struct {
+	Id        int   `json:"id"`
+	MovedBack []int `json:"movedBack"`
+}
+
+type ItemChange struct {
+	Id     int          `json:"id"`
+	Fields []string     `json:"fields"`
+	Before ShoppingItem `json:"before"`
+	After  ShoppingItem `json:"after"`
+}
+
+// ListDiff describes how a list changed between two versions.
+// To is 0 when the diff is made against the current state of the list.
+type ListDiff struct {
+	From       int            `json:"from"`
+	To         int            `json:"to"`
+	ListFields []string       `json:"listFields"`
+	Added      []ShoppingItem `json:"added"`
+	Removed    []ShoppingItem `json:"removed"`
+	Changed    []ItemChange   `json:"changed"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"errors"
+	"github.com/lib/pq"
+)
+
+// Store is a shop of the user with its categories in walking order. Lists assigned to
+// the store can be sorted by the aisles and by the order items were checked off in it.
+type Store struct {
+	Id     int            `json:"id" db:"ID"`
+	Title  string         `json:"title" db:"Title" binding:"required"`
+	Aisles pq.StringArray `json:"aisles" db:"Aisles" swaggertype:"array,string"`
+}
+
+type UpdateStoreInput struct {
+	Title  *string   `json:"title"`
+	Aisles *[]string `json:"aisles"`
+}
+
+func (i UpdateStoreInput) Validate() error {
+	if i.Title == nil && i.Aisles == nil {
+		return errors.New("update structure has no values")
+	}
+	if i.Title != nil && *i.Title == "" {
+		return errors.New("store has no title")
+	}
+
+	return nil
+}
+
+// NormalizeAisles normalizes the categories of the aisles and rejects repeated ones.
+func NormalizeAisles(aisles []string) ([]string, error) {
+	normalized := make([]string, 0, len(aisles))
+	seen := make(map[string]bool, len(aisles))
+	for _, aisle := range aisles {
+		category, err := NormalizeCategory(aisle)
+		if err != nil {
+			return nil, err
+		}
+		if category == "" {
+			return nil, errors.New("aisle has no category")
+		}
+		if seen[category] {
+			return nil, errors.New("aisles contain duplicate category " + category)
+		}
+		seen[category] = true
+		normalized = append(normalized, category)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"github.com/lib/pq"
+	"time"
+)
+
+// PurchaseHistory holds the days a product was purchased on, oldest first and formatted
+// as 2006-01-02, with the title, category, quantity and unit of its latest purchase.
+type PurchaseHistory struct {
+	Title    string         `db:"Title"`
+	Category string         `db:"Category"`
+	Quantity float64        `db:"Quantity"`
+	Unit     string         `db:"Unit"`
+	Days     pq.StringArray `db:"Days"`
+	Last     time.Time      `db:"Last"`
+}
+
+// Suggestion is a product that is probably due to be bought again. Interval is the estimated
+// number of days between purchases and Confidence a score from 0 to 1.
+type Suggestion struct {
+	Title        string    `json:"title"`
+	Category     string    `json:"category"`
+	Quantity     float64   `json:"quantity"`
+	Unit         string    `json:"unit"`
+	Interval     float64   `json:"interval"`
+	LastPurchase time.Time `json:"lastPurchase"`
+	Confidence   float64   `json:"confidence"`
+}
+
+// AddSuggestionInput adds the suggested product with the title to the list.
+type AddSuggestionInput struct {
+	Title string `json:"title" binding:"required"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import "github.com/lib/pq"
+
+const (
+	SyncEntityList = "list"
+	SyncEntityItem = "item"
+)
+
+const (
+	SyncOpCreateList = "createList"
+	SyncOpUpdateList = "updateList"
+	SyncOpDeleteList = "deleteList"
+	SyncOpCreateItem = "createItem"
+	SyncOpUpdateItem = "updateItem"
+	SyncOpDeleteItem = "deleteItem"
+)
+
+const (
+	SyncStatusApplied  = "applied"
+	SyncStatusConflict = "conflict"
+	SyncStatusError    = "error"
+)
+
+type SyncItem struct {
+	ListId int `json:"listId" db:"List_id"`
+	ShoppingItem
+	Clocks FieldClocks    `json:"clocks" db:"Clocks"`
+	Tags   pq.StringArray `json:"tags" db:"Tags" swaggertype:"array,string"`
+}
+
+type Tombstone struct {
+	Entity string `json:"entity" db:"Entity"`
+	Id     int    `json:"id" db:"Entity_id"`
+	ListId int    `json:"listId" db:"List_id"`
+}
+
+// SyncChanges holds everything that changed for the user after the cursor passed by the client.
+// Cursor must be sent back on the next pull.
+type SyncChanges struct {
+	Cursor  int            `json:"cursor"`
+	Lists   []ShoppingList `json:"lists"`
+	Items   []SyncItem     `json:"items"`
+	Deleted []Tombstone    `json:"deleted"`
+}
+
+// SyncMutation is a single change made by the client while offline. ClientId is generated
+// by the client and makes retries of the same create mutation idempotent. Objects created
+// earlier in the same batch can be referenced through ListClientId.
+//
+// Item mutations carrying a Clock are merged instead of being checked against Version:
+// every field keeps the value with the newest clock, and deletes remove only the
+// membership Tags the client has observed, so a concurrent re-add survives.
+type SyncMutation struct {
+	ClientId       string     `json:"clientId" binding:"required"`
+	Op             string     `json:"op" binding:"required"`
+	Id             int        `json:"id"`
+	ListId         int        `json:"listId"`
+	ListClientId   string     `json:"listClientId"`
+	Version        int        `json:"version"`
+	Clock          *Timestamp `json:"clock" swaggertype:"string"`
+	Tags           []string   `json:"tags"`
+	Title          *string    `json:"title"`
+	Description    *string    `json:"description"`
+	Checked        *bool      `json:"checked"`
+	Quantity       *float64   `json:"quantity"`
+	Unit           *string    `json:"unit"`
+	Category       *string    `json:"category"`
+	EstimatedPrice *int64     `json:"estimatedPrice"`
+	ActualPrice    *int64     `json:"actualPrice"`
+}
+
+// ItemInput returns the item fields changed by the mutation.
+func (m SyncMutation) ItemInput() UpdateItemInput {
+	return UpdateItemInput{Title: m.Title, Description: m.Description, Checked: m.Checked, Quantity: m.Quantity,
+		Unit: m.Unit, Category: m.Category, EstimatedPrice: m.EstimatedPrice, ActualPrice: m.ActualPrice}
+}
+
+type SyncPushInput struct {
+	Mutations []SyncMutation `json:"mutations" binding:"required,dive"`
+}
+
+// SyncResult reports the outcome of a mutation. On conflict the current server state
+// of the object is returned, or nothing if it has been deleted.
+type SyncResult struct {
+	ClientId    string        `json:"clientId"`
+	Status      string        `json:"status"`
+	Id          int           `json:"id,omitempty"`
+	Version     int           `json:"version,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	CurrentList *ShoppingList `json:"currentList,omitempty"`
+	CurrentItem *ShoppingItem `json:"currentItem,omitempty"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import "errors"
+
+// ListTemplate is a reusable set of items lists are created from. A template is visible only to
+// the user who created it: lists have no members, so templates are not shared with anyone.
+type ListTemplate struct {
+	Id          int            `json:"id" db:"ID"`
+	Title       string         `json:"title" db:"Title"`
+	Description string         `json:"description" db:"Description"`
+	Items       []TemplateItem `json:"items,omitempty" db:"-"`
+}
+
+type TemplateItem struct {
+	Id          int     `json:"id" db:"ID"`
+	Title       string  `json:"title" db:"Title"`
+	Description string  `json:"description" db:"Description"`
+	Quantity    float64 `json:"quantity" db:"Quantity"`
+	Unit        string  `json:"unit" db:"Unit"`
+	Category    string  `json:"category" db:"Category"`
+}
+
+func validateTemplateItems(items []TemplateItem) error {
+	for _, item := range items {
+		if item.Title == "" {
+			return errors.New("template item has no title")
+		}
+		if item.Quantity < 0 {
+			return errors.New("template item quantity is negative")
+		}
+		if _, err := NormalizeUnit(item.Unit); err != nil {
+			return err
+		}
+		if _, err := NormalizeCategory(item.Category); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateTemplateInput creates a template from the passed items or, when ListId is set,
+// from the items of that list. Title defaults to the title of the list.
+type CreateTemplateInput struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	ListId      int            `json:"listId"`
+	Items       []TemplateItem `json:"items"`
+}
+
+func (i CreateTemplateInput) Validate() error {
+	if i.Title == "" && i.ListId == 0 {
+		return errors.New("template has no title")
+	}
+
+	return validateTemplateItems(i.Items)
+}
+
+// UpdateTemplateInput replaces the template items when Items is set.
+type UpdateTemplateInput struct {
+	Title       *string         `json:"title"`
+	Description *string         `json:"description"`
+	Items       *[]TemplateItem `json:"items"`
+}
+
+func (i UpdateTemplateInput) Validate() error {
+	if i.Title == nil && i.Description == nil && i.Items == nil {
+		return errors.New("update structure has no values")
+	}
+	if i.Title != nil && *i.Title == "" {
+		return errors.New("template has no title")
+	}
+	if i.Items != nil {
+		return validateTemplateItems(*i.Items)
+	}
+
+	return nil
+}
+
+type InstantiateTemplateInput struct {
+	Title string `json:"title"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// What happens to the purchased items of the list when a trip is finished.
+const (
+	PurchasedKeep    = "keep"
+	PurchasedUncheck = "uncheck"
+	PurchasedRemove  = "remove"
+)
+
+// Trip is a visit to a store with a list by Shopper. Items checked off during the trip are recorded
+// as its purchases, a finished trip is the purchase history. Prices are in minor units of Currency.
+type Trip struct {
+	Id         int        `json:"id" db:"ID"`
+	UserId     int        `json:"userId" db:"User_id"`
+	ListId     *int       `json:"listId" db:"List_id"`
+	StoreId    *int       `json:"storeId" db:"Store_id"`
+	Shopper    string     `json:"shopper" db:"Shopper"`
+	Currency   string     `json:"currency" db:"Currency"`
+	Total      int64      `json:"total" db:"Total"`
+	StartedAt  time.Time  `json:"startedAt" db:"Started_at"`
+	FinishedAt *time.Time `json:"finishedAt" db:"Finished_at"`
+	Purchases  []Purchase `json:"purchases,omitempty" db:"-"`
+}
+
+// Purchase is an item checked off during a trip. ItemId is nil once the item is deleted.
+type Purchase struct {
+	Id        int       `json:"id" db:"ID"`
+	ItemId    *int      `json:"itemId" db:"Item_id"`
+	Title     string    `json:"title" db:"Title"`
+	Quantity  float64   `json:"quantity" db:"Quantity"`
+	Unit      string    `json:"unit" db:"Unit"`
+	Category  string    `json:"category" db:"Category"`
+	Price     int64     `json:"price" db:"Price"`
+	CheckedAt time.Time `json:"checkedAt" db:"Checked_at"`
+}
+
+// StartTripInput starts a trip in the store StoreId, the store of the list by default.
+// Shopper is the name of who does the shopping, the username of the user by default.
+type StartTripInput struct {
+	StoreId *int   `json:"storeId"`
+	Shopper string `json:"shopper"`
+}
+
+func (i StartTripInput) Validate() error {
+	if len([]rune(strings.TrimSpace(i.Shopper))) > 50 {
+		return errors.New("shopper is longer than 50 characters")
+	}
+
+	return nil
+}
+
+// FinishTripInput tells what to do with the items of the list purchased during the trip:
+// keep them checked, uncheck them for the next time or remove them.
+type FinishTripInput struct {
+	Purchased string `json:"purchased"`
+}
+
+func (i FinishTripInput) Validate() error {
+	switch i.Purchased {
+	case "", PurchasedKeep, PurchasedUncheck, PurchasedRemove:
+		return nil
+	}
+
+	return errors.New("unknown action for purchased items " + i.Purchased)
+}
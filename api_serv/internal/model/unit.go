@@ -0,0 +1,144 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const DefaultUnit = "pcs"
+
+const (
+	DimensionMass   = "mass"
+	DimensionVolume = "volume"
+	DimensionCount  = "count"
+	DimensionPack   = "pack"
+)
+
+// Unit is a unit of the built-in catalog. Factor converts a quantity in the unit
+// to the base unit of its dimension: grams, millilitres, pieces or packs.
+type Unit struct {
+	Code      string  `json:"code"`
+	Dimension string  `json:"dimension"`
+	Factor    float64 `json:"factor"`
+}
+
+var units = []Unit{
+	{Code: "g", Dimension: DimensionMass, Factor: 1},
+	{Code: "kg", Dimension: DimensionMass, Factor: 1000},
+	{Code: "lb", Dimension: DimensionMass, Factor: 453.59237},
+	{Code: "oz", Dimension: DimensionMass, Factor: 28.349523125},
+	{Code: "ml", Dimension: DimensionVolume, Factor: 1},
+	{Code: "l", Dimension: DimensionVolume, Factor: 1000},
+	{Code: "cup", Dimension: DimensionVolume, Factor: 236.5882365},
+	{Code: DefaultUnit, Dimension: DimensionCount, Factor: 1},
+	{Code: "pack", Dimension: DimensionPack, Factor: 1},
+}
+
+var unitAliases = map[string]string{
+	"gram": "g", "grams": "g",
+	"kilogram": "kg", "kilograms": "kg", "kilo": "kg", "kilos": "kg",
+	"lbs": "lb", "pound": "lb", "pounds": "lb",
+	"ounce": "oz", "ounces": "oz",
+	"millilitre": "ml", "millilitres": "ml", "milliliter": "ml", "milliliters": "ml",
+	"litre": "l", "litres": "l", "liter": "l", "liters": "l", "cups": "cup",
+	"piece": "pcs", "pieces": "pcs", "pc": "pcs",
+	"packs": "pack", "package": "pack", "packages": "pack", "bag": "pack", "bags": "pack",
+
+	"г": "g", "гр": "g", "грамм": "g", "грамма": "g", "граммов": "g",
+	"кг": "kg", "кило": "kg", "килограмм": "kg", "килограмма": "kg", "килограммов": "kg",
+	"фунт": "lb", "фунта": "lb", "фунтов": "lb",
+	"унция": "oz", "унции": "oz", "унций": "oz",
+	"мл": "ml", "миллилитр": "ml", "миллилитра": "ml", "миллилитров": "ml",
+	"л": "l", "литр": "l", "литра": "l", "литров": "l",
+	"стакан": "cup", "стакана": "cup", "стаканов": "cup",
+	"шт": "pcs", "штука": "pcs", "штуки": "pcs", "штук": "pcs",
+	"уп": "pack", "упак": "pack", "упаковка": "pack", "упаковки": "pack", "упаковок": "pack",
+	"пачка": "pack", "пачки": "pack", "пачек": "pack", "пакет": "pack", "пакета": "pack", "пакетов": "pack",
+}
+
+// referenceUnits are the units prices of a dimension are compared in.
+var referenceUnits = map[string]string{
+	DimensionMass:   "kg",
+	DimensionVolume: "l",
+	DimensionCount:  DefaultUnit,
+	DimensionPack:   "pack",
+}
+
+// ReferenceUnit returns the unit prices of the unit are compared in.
+func ReferenceUnit(code string) (string, bool) {
+	unit, ok := LookupUnit(code)
+	if !ok {
+		return "", false
+	}
+
+	return referenceUnits[unit.Dimension], true
+}
+
+// Units returns the unit catalog.
+func Units() []Unit {
+	return append([]Unit(nil), units...)
+}
+
+// LookupUnit finds a unit of the catalog by its code or a common alias, ignoring case.
+// An empty code is the default unit.
+func LookupUnit(code string) (Unit, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		code = DefaultUnit
+	}
+	if alias, ok := unitAliases[code]; ok {
+		code = alias
+	}
+
+	for _, unit := range units {
+		if unit.Code == code {
+			return unit, true
+		}
+	}
+
+	return Unit{}, false
+}
+
+// NormalizeUnit returns the catalog code of the unit.
+func NormalizeUnit(code string) (string, error) {
+	unit, ok := LookupUnit(code)
+	if !ok {
+		return "", errors.New("unknown unit " + code)
+	}
+
+	return unit.Code, nil
+}
+
+// UnitsCompatible reports whether quantities in both units can be converted into each other.
+func UnitsCompatible(from, to string) bool {
+	fromUnit, ok := LookupUnit(from)
+	if !ok {
+		return false
+	}
+	toUnit, ok := LookupUnit(to)
+
+	return ok && fromUnit.Dimension == toUnit.Dimension
+}
+
+// ConvertQuantity converts the quantity between compatible units, rounded to the
+// three decimal places the quantity is stored with.
+func ConvertQuantity(quantity float64, from, to string) (float64, error) {
+	fromUnit, ok := LookupUnit(from)
+	if !ok {
+		return 0, errors.New("unknown unit " + from)
+	}
+	toUnit, ok := LookupUnit(to)
+	if !ok {
+		return 0, errors.New("unknown unit " + to)
+	}
+	if fromUnit.Dimension != toUnit.Dimension {
+		return 0, errors.New("cannot convert " + fromUnit.Code + " to " + toUnit.Code)
+	}
+
+	return RoundQuantity(quantity * fromUnit.Factor / toUnit.Factor), nil
+}
+
+func RoundQuantity(quantity float64) float64 {
+	return math.Round(quantity*1000) / 1000
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+type CategoryPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCategoryPostgres(db *sqlx.DB) *CategoryPostgres {
+	return &CategoryPostgres{db: db}
+}
+
+// Lookup returns the category the user has given to items with the title, the title must be normalized.
+func (r *CategoryPostgres) Lookup(userId int, title string) (string, error) {
+	var category string
+	query := fmt.Sprintf(`SELECT D."Category" FROM "Category_Dictionary" D WHERE D."User_id" = $1 AND D."Title" = $2`)
+	err := r.db.Get(&category, query, userId, title)
+
+	return category, err
+}
+
+// Remember stores the category of items with the title in the dictionary of the user.
+func (r *CategoryPostgres) Remember(userId int, title, category string) error {
+	query := fmt.Sprintf(`INSERT INTO "Category_Dictionary" ("User_id", "Title", "Category") VALUES ($1, $2, $3)
+									ON CONFLICT ("User_id", "Title") DO UPDATE SET "Category" = EXCLUDED."Category"`)
+	_, err := r.db.Exec(query, userId, title, category)
+
+	return err
+}
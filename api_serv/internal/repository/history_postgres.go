@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type ItemHistoryPostgres struct {
+	db *sqlx.DB
+}
+
+func NewItemHistoryPostgres(db *sqlx.DB) *ItemHistoryPostgres {
+	return &ItemHistoryPostgres{db: db}
+}
+
+// Search returns up to limit titles of the user history that start with the normalized key,
+// have a word starting with it or are similar to it by trigrams, the most similar first.
+func (r *ItemHistoryPostgres) Search(userId int, key string, limit int) ([]model.Completion, error) {
+	completions := make([]model.Completion, 0)
+	prefix := likeEscaper.Replace(key) + "%"
+	query := fmt.Sprintf(`SELECT H."Last_title", H."Category", H."Quantity", H."Unit", H."Price", H."Currency",
+										H."Uses", H."Last_used", word_similarity($2, H."Title") AS "Similarity",
+										H."Title" LIKE $3 OR H."Title" LIKE '%% ' || $3 AS "Prefix"
+									FROM "Item_History" H
+									WHERE H."User_id" = $1 AND (H."Title" LIKE $3 OR H."Title" LIKE '%% ' || $3 OR $2 <%% H."Title")
+									ORDER BY "Prefix" DESC, "Similarity" DESC, H."Uses" DESC
+									LIMIT $4`)
+	err := r.db.Select(&completions, query, userId, key, prefix, limit)
+
+	return completions, err
+}
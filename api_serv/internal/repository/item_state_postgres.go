@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ItemStatePostgres struct {
+	db *sqlx.DB
+}
+
+func NewItemStatePostgres(db *sqlx.DB) *ItemStatePostgres {
+	return &ItemStatePostgres{db: db}
+}
+
+func (r *ItemStatePostgres) Get(userId, itemId int) (model.SyncItem, error) {
+	var item model.SyncItem
+	query := fmt.Sprintf(`SELECT I."ID", I."List_id", I."Title", I."Description", I."Checked", I."Quantity", I."Unit", I."Category", I."Estimated_price", I."Actual_price", I."Position", I."Version", I."Clocks",
+										ARRAY(SELECT T."Tag" FROM "Item_Tag" T WHERE T."Item_id" = I."ID" AND NOT T."Removed" ORDER BY T."Tag") AS "Tags"
+									FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+									WHERE I."ID" = $1 AND L."User_id" = $2`)
+	err := r.db.Get(&item, query, itemId, userId)
+
+	return item, err
+}
+
+func (r *ItemStatePostgres) GetTags(userId, itemId int) ([]model.MembershipTag, error) {
+	var tags []model.MembershipTag
+	query := fmt.Sprintf(`SELECT T."Tag", T."Removed" FROM "Item_Tag" T INNER JOIN "Shopping_List" L on T."List_id" = L."ID"
+									WHERE T."Item_id" = $1 AND L."User_id" = $2`)
+	err := r.db.Select(&tags, query, itemId, userId)
+
+	return tags, err
+}
+
+// AddTag stores a new add tag. A tag that has been removed once stays removed.
+func (r *ItemStatePostgres) AddTag(listId, itemId int, tag string) error {
+	query := fmt.Sprintf(`INSERT INTO "Item_Tag" ("Item_id", "List_id", "Tag") VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`)
+	_, err := r.db.Exec(query, itemId, listId, tag)
+
+	return err
+}
+
+// RemoveTags marks tags as removed. Tags that have not arrived yet are stored removed
+// so that a delayed add with the same tag does not bring the item back.
+func (r *ItemStatePostgres) RemoveTags(listId, itemId int, tags []string) error {
+	query := fmt.Sprintf(`INSERT INTO "Item_Tag" ("Item_id", "List_id", "Tag", "Removed") SELECT $1, $2, unnest($3::text[]), true
+									ON CONFLICT ("Item_id", "Tag") DO UPDATE SET "Removed" = true`)
+	_, err := r.db.Exec(query, itemId, listId, pq.Array(tags))
+
+	return err
+}
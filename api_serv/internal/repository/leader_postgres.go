@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"sync"
+)
+
+// SchedulerLockKey is the advisory lock taken by the replica that runs scheduled jobs.
+const SchedulerLockKey int64 = 0x73686f70
+
+// LeaderPostgres elects a single leader among service replicas with a session-level
+// advisory lock. The lock lives as long as the dedicated connection holding it.
+type LeaderPostgres struct {
+	db   *sqlx.DB
+	key  int64
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewLeaderPostgres(db *sqlx.DB, key int64) *LeaderPostgres {
+	return &LeaderPostgres{db: db, key: key}
+}
+
+// TryAcquire reports whether this replica holds the lock, taking it if it is free.
+func (r *LeaderPostgres) TryAcquire(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn != nil {
+		if err := r.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		// the connection and the lock with it are lost
+		r.conn.Close()
+		r.conn = nil
+	}
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, r.key).Scan(&acquired); err != nil || !acquired {
+		conn.Close()
+		return false, err
+	}
+
+	r.conn = conn
+	return true, nil
+}
+
+func (r *LeaderPostgres) Release() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		return nil
+	}
+
+	_, err := r.conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, r.key)
+	r.conn.Close()
+	r.conn = nil
+
+	return err
+}
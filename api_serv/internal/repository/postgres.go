@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -11,6 +13,8 @@ const (
 	itemTable     = "Shopping_Item"
 	listItemTable = "List_Items"
 	userListTable = "User_List"
+	snapshotTable = "List_Snapshot"
+	snapItemTable = "Snapshot_Item"
 )
 
 type Config struct {
@@ -38,3 +42,20 @@ func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// checkVersionedResult reports model.ErrVersionMismatch when a conditional statement affected no rows.
+func checkVersionedResult(res sql.Result, version int) error {
+	if version == 0 {
+		return nil
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return model.ErrVersionMismatch
+	}
+
+	return nil
+}
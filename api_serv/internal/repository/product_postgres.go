@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+const observationColumns = `O."ID", O."Store_id", S."Title" AS "Store_title", O."Price", O."Quantity", O."Unit", O."Currency", O."Observed_at"`
+
+type ProductPostgres struct {
+	db *sqlx.DB
+}
+
+func NewProductPostgres(db *sqlx.DB) *ProductPostgres {
+	return &ProductPostgres{db: db}
+}
+
+func (r *ProductPostgres) GetAll(userId int) ([]model.Product, error) {
+	var products []model.Product
+	query := fmt.Sprintf(`SELECT P."ID", P."Title" FROM "Product" P WHERE P."User_id" = $1 ORDER BY P."Title"`)
+	err := r.db.Select(&products, query, userId)
+
+	return products, err
+}
+
+func (r *ProductPostgres) GetById(userId, productId int) (model.Product, error) {
+	var product model.Product
+	query := fmt.Sprintf(`SELECT P."ID", P."Title" FROM "Product" P WHERE P."User_id" = $1 AND P."ID" = $2`)
+	err := r.db.Get(&product, query, userId, productId)
+
+	return product, err
+}
+
+// GetPrices returns the price observations of the product, newest first.
+func (r *ProductPostgres) GetPrices(productId int) ([]model.PriceObservation, error) {
+	var observations []model.PriceObservation
+	query := fmt.Sprintf(`SELECT %s FROM "Price_Observation" O LEFT JOIN "Store" S on O."Store_id" = S."ID"
+									WHERE O."Product_id" = $1 ORDER BY O."Observed_at" DESC, O."ID" DESC`, observationColumns)
+	err := r.db.Select(&observations, query, productId)
+
+	return observations, err
+}
+
+// GetLatestPrice returns the latest price of the product with the normalized title in the currency,
+// observed in the store if there is one there.
+func (r *ProductPostgres) GetLatestPrice(userId int, title, currency string, storeId *int) (model.PriceObservation, error) {
+	var observation model.PriceObservation
+	query := fmt.Sprintf(`SELECT %s FROM "Price_Observation" O
+									INNER JOIN "Product" P on O."Product_id" = P."ID"
+									LEFT JOIN "Store" S on O."Store_id" = S."ID"
+									WHERE P."User_id" = $1 AND P."Title" = $2 AND O."Currency" = $3
+									ORDER BY O."Store_id" IS NOT DISTINCT FROM $4 DESC, O."Observed_at" DESC, O."ID" DESC
+									LIMIT 1`, observationColumns)
+	err := r.db.Get(&observation, query, userId, title, currency, storeId)
+
+	return observation, err
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type PurchasePostgres struct {
+	db *sqlx.DB
+}
+
+func NewPurchasePostgres(db *sqlx.DB) *PurchasePostgres {
+	return &PurchasePostgres{db: db}
+}
+
+// GetHistory returns the purchase days of every product the user has bought since the time,
+// products are identified by the normalized title like service.categoryKey.
+func (r *PurchasePostgres) GetHistory(userId int, since time.Time) ([]model.PurchaseHistory, error) {
+	history := make([]model.PurchaseHistory, 0)
+	query := fmt.Sprintf(`SELECT (array_agg(P."Title" ORDER BY P."Checked_at" DESC))[1] AS "Title",
+										(array_agg(P."Category" ORDER BY P."Checked_at" DESC))[1] AS "Category",
+										(array_agg(P."Quantity" ORDER BY P."Checked_at" DESC))[1] AS "Quantity",
+										(array_agg(P."Unit" ORDER BY P."Checked_at" DESC))[1] AS "Unit",
+										array_agg(DISTINCT to_char(P."Checked_at", 'YYYY-MM-DD') ORDER BY to_char(P."Checked_at", 'YYYY-MM-DD')) AS "Days",
+										max(P."Checked_at") AS "Last"
+									FROM "Purchase" P INNER JOIN "Trip" T on P."Trip_id" = T."ID"
+									WHERE T."User_id" = $1 AND P."Checked_at" >= $2
+									GROUP BY lower(regexp_replace(btrim(P."Title"), '\s+', ' ', 'g'))`)
+	err := r.db.Select(&history, query, userId, since)
+
+	return history, err
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// spendingGroups are the key and label expressions of the spending report groups.
+var spendingGroups = map[string][2]string{
+	model.ReportGroupMonth:    {`to_char(P."Checked_at", 'YYYY-MM')`, `to_char(P."Checked_at", 'YYYY-MM')`},
+	model.ReportGroupWeek:     {`to_char(P."Checked_at", 'IYYY-"W"IW')`, `to_char(P."Checked_at", 'IYYY-"W"IW')`},
+	model.ReportGroupCategory: {`COALESCE(NULLIF(P."Category", ''), 'other')`, `COALESCE(NULLIF(P."Category", ''), 'other')`},
+	model.ReportGroupStore:    {`COALESCE(T."Store_id"::text, '')`, `COALESCE(S."Title", '')`},
+	model.ReportGroupMember:   {`T."Shopper"`, `T."Shopper"`},
+}
+
+type ReportPostgres struct {
+	db *sqlx.DB
+}
+
+func NewReportPostgres(db *sqlx.DB) *ReportPostgres {
+	return &ReportPostgres{db: db}
+}
+
+// Spending sums the purchases of the trips of the user by group and currency, the groups in order of their keys.
+func (r *ReportPostgres) Spending(userId int, filter model.SpendingFilter) ([]model.SpendingRow, error) {
+	group, ok := spendingGroups[filter.Group]
+	if !ok {
+		return nil, errors.New("unknown report group " + filter.Group)
+	}
+
+	rows := make([]model.SpendingRow, 0)
+	query := fmt.Sprintf(`SELECT %s AS "Key", min(%s) AS "Label", T."Currency", sum(P."Price") AS "Total",
+										count(*) AS "Purchases", count(DISTINCT T."ID") AS "Trips"
+									FROM "Purchase" P
+									INNER JOIN "Trip" T on P."Trip_id" = T."ID"
+									LEFT JOIN "Store" S on T."Store_id" = S."ID"
+									WHERE T."User_id" = $1
+										AND ($2::date IS NULL OR P."Checked_at" >= $2::date)
+										AND ($3::date IS NULL OR P."Checked_at" < $3::date + 1)
+									GROUP BY 1, T."Currency"
+									ORDER BY 1, T."Currency"`, group[0], group[1])
+	err := r.db.Select(&rows, query, userId, filter.From, filter.To)
+
+	return rows, err
+}
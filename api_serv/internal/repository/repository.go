@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/jmoiron/sqlx"
+	"time"
 )
 
 type Authorization interface {
@@ -12,24 +14,141 @@ type Authorization interface {
 
 type List interface {
 	Create(userId int, list model.ShoppingList) (int, error)
-	GetAll(userId int) ([]model.ShoppingList, error)
+	GetAll(userId int, archived *bool) ([]model.ShoppingList, error)
 	GetById(userId, listId int) (model.ShoppingList, error)
-	Update(userId, listId int, input model.UpdateListInput) error
-	Delete(userId, listId int) error
+	Update(userId, listId int, input model.UpdateListInput, version int) error
+	Delete(userId, listId int, version int) error
+	Reorder(userId int, ids []int) error
+	Duplicate(userId, listId int, title string, uncheckedOnly bool) (int, error)
+	Merge(userId, targetId int, ids []int) error
+	Split(userId, listId int, title string, itemIds []int) (int, error)
+	GetTotals(listId int) (model.ListTotals, error)
 }
 
 type Item interface {
 	Create(listId int, item model.ShoppingItem) (int, error)
 	GetAll(userId, listId int) ([]model.ShoppingItem, error)
 	GetById(userId, itemId int) (model.ShoppingItem, error)
-	Update(userId, itemId int, input model.UpdateItemInput) error
-	Delete(userId, itemId int) error
+	Update(userId, itemId int, input model.UpdateItemInput, version int) error
+	CheckOff(userId, itemId int, input model.UpdateItemInput, version int) error
+	Delete(userId, itemId int, version int) error
+	SetPosition(userId, itemId int, input model.ItemPositionInput, version int) (string, error)
+	Move(userId, listId int, itemIds []int) error
+	Copy(userId, listId int, itemIds []int) ([]int, error)
+	Bulk(userId, listId int, ops []model.BulkItemOperation) ([]model.BulkItemResult, error)
+	AddAll(userId, listId int, changes []model.ItemUpdate, items []model.ShoppingItem) ([]int, error)
+}
+
+type Category interface {
+	Lookup(userId int, title string) (string, error)
+	Remember(userId int, title, category string) error
+}
+
+type Product interface {
+	GetAll(userId int) ([]model.Product, error)
+	GetById(userId, productId int) (model.Product, error)
+	GetPrices(productId int) ([]model.PriceObservation, error)
+	GetLatestPrice(userId int, title, currency string, storeId *int) (model.PriceObservation, error)
+}
+
+type Trip interface {
+	Start(userId, listId int, storeId *int, shopper string) (int, error)
+	GetAll(userId int) ([]model.Trip, error)
+	GetById(userId, tripId int) (model.Trip, error)
+	Finish(userId, tripId int, purchased string, clocks model.FieldClocks) error
+}
+
+type Purchase interface {
+	GetHistory(userId int, since time.Time) ([]model.PurchaseHistory, error)
+}
+
+type ItemHistory interface {
+	Search(userId int, key string, limit int) ([]model.Completion, error)
+}
+
+type Search interface {
+	Search(userId int, filter model.SearchFilter) (model.SearchPage, error)
+}
+
+type Report interface {
+	Spending(userId int, filter model.SpendingFilter) ([]model.SpendingRow, error)
+}
+
+type Store interface {
+	Create(userId int, store model.Store) (int, error)
+	GetAll(userId int) ([]model.Store, error)
+	GetById(userId, storeId int) (model.Store, error)
+	Update(userId, storeId int, input model.UpdateStoreInput) error
+	Delete(userId, storeId int) error
+	GetRanks(storeId int) (map[string]float64, error)
+}
+
+type Snapshot interface {
+	Create(userId, listId int) (int, error)
+	GetAll(userId, listId int) ([]model.ListSnapshot, error)
+	GetById(userId, snapshotId int) (model.ListSnapshot, error)
+	Restore(userId, snapshotId int) (model.SnapshotRestore, error)
+}
+
+type Sync interface {
+	GetChanges(userId, since int) (model.SyncChanges, error)
+	GetClientId(userId int, clientId string) (string, int, error)
+	SaveClientId(userId int, clientId, entity string, entityId int) error
+	CreateList(userId int, clientId string, list model.ShoppingList) (string, int, error)
+	CreateItem(userId int, clientId string, listId int, item model.ShoppingItem, clocks model.FieldClocks) (string, int, error)
+}
+
+type ItemState interface {
+	Get(userId, itemId int) (model.SyncItem, error)
+	GetTags(userId, itemId int) ([]model.MembershipTag, error)
+	AddTag(listId, itemId int, tag string) error
+	RemoveTags(listId, itemId int, tags []string) error
+}
+
+type Template interface {
+	Create(userId int, template model.ListTemplate) (int, error)
+	GetAll(userId int) ([]model.ListTemplate, error)
+	GetById(userId, templateId int) (model.ListTemplate, error)
+	Update(userId, templateId int, input model.UpdateTemplateInput) error
+	Delete(userId, templateId int) error
+	Instantiate(userId, templateId int, title string) (int, error)
+	AddToList(userId, templateId, listId int) error
+}
+
+type Schedule interface {
+	Create(userId int, schedule model.Schedule) (int, error)
+	GetAll(userId int) ([]model.Schedule, error)
+	GetById(userId, scheduleId int) (model.Schedule, error)
+	Update(userId int, schedule model.Schedule) error
+	Delete(userId, scheduleId int) error
+	GetDue(now time.Time, limit int) ([]model.Schedule, error)
+	Advance(scheduleId int, from time.Time, next *time.Time, ranAt time.Time) (bool, error)
+	Run(schedule model.Schedule, next *time.Time, ranAt time.Time) (bool, error)
+}
+
+type Leader interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release() error
 }
 
 type Repository struct {
 	Authorization
 	List
 	Item
+	Category
+	Store
+	Product
+	Trip
+	Purchase
+	ItemHistory
+	Search
+	Report
+	Snapshot
+	Sync
+	ItemState
+	Template
+	Schedule
+	Leader
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
@@ -37,5 +156,19 @@ func NewRepository(db *sqlx.DB) *Repository {
 		Authorization: NewAuthPostgres(db),
 		List:          NewShoppingListPostgres(db),
 		Item:          NewShoppingItemPostgres(db),
+		Category:      NewCategoryPostgres(db),
+		Store:         NewStorePostgres(db),
+		Product:       NewProductPostgres(db),
+		Trip:          NewTripPostgres(db),
+		Purchase:      NewPurchasePostgres(db),
+		ItemHistory:   NewItemHistoryPostgres(db),
+		Search:        NewSearchPostgres(db),
+		Report:        NewReportPostgres(db),
+		Snapshot:      NewSnapshotPostgres(db),
+		Sync:          NewSyncPostgres(db),
+		ItemState:     NewItemStatePostgres(db),
+		Template:      NewTemplatePostgres(db),
+		Schedule:      NewSchedulePostgres(db),
+		Leader:        NewLeaderPostgres(db, SchedulerLockKey),
 	}
 }
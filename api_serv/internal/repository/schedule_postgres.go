@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const scheduleColumns = `S."ID", S."User_id", S."Template_id", S."List_id", S."Title", S."Rule", S."Start", S."Timezone",
+	S."Enabled", S."Next_run", S."Last_run"`
+
+type SchedulePostgres struct {
+	db *sqlx.DB
+}
+
+func NewSchedulePostgres(db *sqlx.DB) *SchedulePostgres {
+	return &SchedulePostgres{db: db}
+}
+
+func (r *SchedulePostgres) Create(userId int, schedule model.Schedule) (int, error) {
+	var id int
+	query := fmt.Sprintf(`INSERT INTO "List_Schedule" ("User_id", "Template_id", "List_id", "Title", "Rule", "Start", "Timezone", "Enabled", "Next_run")
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING "ID"`)
+	row := r.db.QueryRow(query, userId, schedule.TemplateId, schedule.ListId, schedule.Title, schedule.Rule, schedule.Start,
+		schedule.Timezone, schedule.Enabled, schedule.NextRun)
+	err := row.Scan(&id)
+
+	return id, err
+}
+
+func (r *SchedulePostgres) GetAll(userId int) ([]model.Schedule, error) {
+	var schedules []model.Schedule
+	query := fmt.Sprintf(`SELECT %s FROM "List_Schedule" S WHERE S."User_id" = $1 ORDER BY S."ID"`, scheduleColumns)
+	err := r.db.Select(&schedules, query, userId)
+
+	return schedules, err
+}
+
+func (r *SchedulePostgres) GetById(userId, scheduleId int) (model.Schedule, error) {
+	var schedule model.Schedule
+	query := fmt.Sprintf(`SELECT %s FROM "List_Schedule" S WHERE S."User_id" = $1 AND S."ID" = $2`, scheduleColumns)
+	err := r.db.Get(&schedule, query, userId, scheduleId)
+
+	return schedule, err
+}
+
+func (r *SchedulePostgres) Update(userId int, schedule model.Schedule) error {
+	query := fmt.Sprintf(`UPDATE "List_Schedule" S SET "Template_id" = $1, "List_id" = $2, "Title" = $3, "Rule" = $4, "Start" = $5,
+									"Timezone" = $6, "Enabled" = $7, "Next_run" = $8 WHERE S."User_id" = $9 AND S."ID" = $10`)
+	_, err := r.db.Exec(query, schedule.TemplateId, schedule.ListId, schedule.Title, schedule.Rule, schedule.Start,
+		schedule.Timezone, schedule.Enabled, schedule.NextRun, userId, schedule.Id)
+
+	return err
+}
+
+func (r *SchedulePostgres) Delete(userId, scheduleId int) error {
+	query := fmt.Sprintf(`DELETE FROM "List_Schedule" S WHERE S."User_id" = $1 AND S."ID" = $2`)
+	_, err := r.db.Exec(query, userId, scheduleId)
+
+	return err
+}
+
+// GetDue returns enabled schedules of all users whose next run is not later than now.
+func (r *SchedulePostgres) GetDue(now time.Time, limit int) ([]model.Schedule, error) {
+	var schedules []model.Schedule
+	query := fmt.Sprintf(`SELECT %s FROM "List_Schedule" S WHERE S."Enabled" AND S."Next_run" <= $1
+									ORDER BY S."Next_run" LIMIT $2`, scheduleColumns)
+	err := r.db.Select(&schedules, query, now, limit)
+
+	return schedules, err
+}
+
+// Advance moves the schedule to its next run. It returns false if the schedule has already
+// been advanced from the given run, so every occurrence is processed once.
+func (r *SchedulePostgres) Advance(scheduleId int, from time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	return advanceSchedule(r.db, scheduleId, from, next, ranAt)
+}
+
+// Run creates the list of the due schedule, or adds the items to its list, and advances it from
+// its next run in one transaction. It returns false without running the schedule if it has
+// already been advanced from that run.
+func (r *SchedulePostgres) Run(schedule model.Schedule, next *time.Time, ranAt time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	// the update locks the schedule, a concurrent run waits for the commit and finds it advanced
+	advanced, err := advanceSchedule(tx, schedule.Id, *schedule.NextRun, next, ranAt)
+	if err != nil || !advanced {
+		tx.Rollback()
+		return false, err
+	}
+
+	if schedule.ListId == nil {
+		_, err = instantiateTemplate(tx, schedule.UserId, schedule.TemplateId, schedule.Title)
+	} else {
+		err = addTemplateItems(tx, schedule.UserId, schedule.TemplateId, *schedule.ListId)
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func advanceSchedule(db sqlx.Execer, scheduleId int, from time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	query := fmt.Sprintf(`UPDATE "List_Schedule" S SET "Next_run" = $1, "Last_run" = $2 WHERE S."ID" = $3 AND S."Next_run" = $4`)
+	res, err := db.Exec(query, next, ranAt, scheduleId, from)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected == 1, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// searchHits matches the lists and items of the user against the query $2 by the full-text
+// search in Russian and English and by the trigram word similarity of titles for typos.
+const searchHits = `WITH Q AS (SELECT websearch_to_tsquery('russian', $2) || websearch_to_tsquery('english', $2) AS "Query"),
+									H AS (
+										SELECT 'list' AS "Kind", L."ID", L."ID" AS "List_id", L."Title" AS "List_title", L."Title",
+											COALESCE(L."Description", '') AS "Description",
+											ts_rank(L."Search", Q."Query") + word_similarity($2, L."Title") AS "Rank"
+										FROM "Shopping_List" L, Q
+										WHERE L."User_id" = $1 AND (L."Search" @@ Q."Query" OR $2 <%% L."Title")
+										UNION ALL
+										SELECT 'item', I."ID", L."ID", L."Title", I."Title", COALESCE(I."Description", ''),
+											ts_rank(I."Search", Q."Query") + word_similarity($2, I."Title")
+										FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID", Q
+										WHERE L."User_id" = $1 AND (I."Search" @@ Q."Query" OR $2 <%% I."Title"))`
+
+const headlineOptions = `StartSel=<mark>, StopSel=</mark>`
+
+// escapeHTML is an SQL expression escaping the HTML special characters of the text column
+// %s, so that the <mark> tags added by ts_headline are the only markup of snippets.
+const escapeHTML = `replace(replace(replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+type SearchPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+// Search returns the page of lists and items of the user matching the query, the best matching first,
+// with HTML escaped list titles and the matching words of HTML escaped titles and descriptions highlighted.
+func (r *SearchPostgres) Search(userId int, filter model.SearchFilter) (model.SearchPage, error) {
+	page := model.SearchPage{Results: make([]model.SearchResult, 0)}
+
+	query := fmt.Sprintf(`%s SELECT count(*) FROM H`, searchHits)
+	if err := r.db.Get(&page.Total, query, userId, filter.Query); err != nil || page.Total == 0 {
+		return page, err
+	}
+
+	query = fmt.Sprintf(`%s SELECT H."Kind", H."ID", H."List_id", %s AS "List_title",
+										ts_headline('russian', %s, Q."Query", 'HighlightAll=true, %s') AS "Title",
+										ts_headline('russian', %s, Q."Query", 'MaxFragments=2, MaxWords=20, MinWords=5, %s') AS "Description",
+										H."Rank"
+									FROM H, Q
+									ORDER BY H."Rank" DESC, H."Kind", H."ID"
+									LIMIT $3 OFFSET $4`, searchHits, fmt.Sprintf(escapeHTML, `H."List_title"`),
+		fmt.Sprintf(escapeHTML, `H."Title"`), headlineOptions,
+		fmt.Sprintf(escapeHTML, `H."Description"`), headlineOptions)
+	err := r.db.Select(&page.Results, query, userId, filter.Query, filter.Limit, filter.Offset)
+
+	return page, err
+}
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"strings"
 )
 
@@ -18,8 +21,11 @@ func NewShoppingItemPostgres(db *sqlx.DB) *ShoppingItemPostgres {
 func (r *ShoppingItemPostgres) Create(listId int, item model.ShoppingItem) (int, error) {
 	var itemId int
 
-	createItemQuery := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("Title", "List_id", "Description") VALUES ($1, $2, $3) RETURNING "ID"`)
-	row := r.db.QueryRow(createItemQuery, item.Title, listId, item.Description)
+	createItemQuery := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("Title", "List_id", "Description", "Quantity", "Unit", "Category",
+										"Estimated_price", "Actual_price")
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING "ID"`)
+	row := r.db.QueryRow(createItemQuery, item.Title, listId, item.Description, item.Quantity, item.Unit, item.Category,
+		item.EstimatedPrice, item.ActualPrice)
 	err := row.Scan(&itemId)
 
 	return itemId, err
@@ -27,8 +33,8 @@ func (r *ShoppingItemPostgres) Create(listId int, item model.ShoppingItem) (int,
 
 func (r *ShoppingItemPostgres) GetAll(userId, listId int) ([]model.ShoppingItem, error) {
 	var items []model.ShoppingItem
-	query := fmt.Sprintf(`SELECT I."ID", I."Title", I."Description", I."Checked" FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
-									WHERE I."List_id" = $1 AND L."User_id" = $2`)
+	query := fmt.Sprintf(`SELECT I."ID", I."Title", I."Description", I."Checked", I."Quantity", I."Unit", I."Category", I."Estimated_price", I."Actual_price", I."Position", I."Version" FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+									WHERE I."List_id" = $1 AND L."User_id" = $2 ORDER BY I."Position", I."ID"`)
 	if err := r.db.Select(&items, query, listId, userId); err != nil {
 		return nil, err
 	}
@@ -38,7 +44,7 @@ func (r *ShoppingItemPostgres) GetAll(userId, listId int) ([]model.ShoppingItem,
 
 func (r *ShoppingItemPostgres) GetById(userId, itemId int) (model.ShoppingItem, error) {
 	var item model.ShoppingItem
-	query := fmt.Sprintf(`SELECT I."ID", I."Title", I."Description", I."Checked" FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+	query := fmt.Sprintf(`SELECT I."ID", I."Title", I."Description", I."Checked", I."Quantity", I."Unit", I."Category", I."Estimated_price", I."Actual_price", I."Position", I."Version" FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
 									WHERE I."ID" = $1 AND L."User_id" = $2`)
 	if err := r.db.Get(&item, query, itemId, userId); err != nil {
 		return item, err
@@ -47,14 +53,249 @@ func (r *ShoppingItemPostgres) GetById(userId, itemId int) (model.ShoppingItem,
 	return item, nil
 }
 
-func (r *ShoppingItemPostgres) Delete(userId, itemId int) error {
+func (r *ShoppingItemPostgres) Delete(userId, itemId int, version int) error {
 	query := fmt.Sprintf(`DELETE FROM "Shopping_Item" I USING "Shopping_List" L
-									WHERE L."ID" = I."List_id" AND L."User_id" = $1 AND I."ID" = $2`)
-	_, err := r.db.Exec(query, userId, itemId)
+									WHERE L."ID" = I."List_id" AND L."User_id" = $1 AND I."ID" = $2 AND ($3 = 0 OR I."Version" = $3)`)
+	res, err := r.db.Exec(query, userId, itemId, version)
+	if err != nil {
+		return err
+	}
+
+	return checkVersionedResult(res, version)
+}
+
+// Update changes the item and increments its version. A non-zero version makes the update
+// conditional: model.ErrVersionMismatch is returned when the stored version differs.
+func (r *ShoppingItemPostgres) Update(userId, itemId int, input model.UpdateItemInput, version int) error {
+	return r.update(userId, itemId, input, version, false)
+}
+
+// CheckOff updates the item like Update when the user checks it off on its own, which
+// also teaches the store of the list where the item is on the way through it.
+func (r *ShoppingItemPostgres) CheckOff(userId, itemId int, input model.UpdateItemInput, version int) error {
+	return r.update(userId, itemId, input, version, true)
+}
+
+func (r *ShoppingItemPostgres) update(userId, itemId int, input model.UpdateItemInput, version int, learn bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if learn {
+		// read by learn_store_order until the end of the transaction
+		if _, err = tx.Exec(`SELECT set_config('shopping_list.learn_store_order', 'on', true)`); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	setQuery, args := itemSetQuery(input)
+	argId := len(args) + 1
+
+	query := fmt.Sprintf(`UPDATE "Shopping_Item" I SET %s FROM "Shopping_List" L
+									WHERE L."ID" = I."List_id" AND L."User_id" = $%d AND I."ID" = $%d
+									AND ($%d = 0 OR I."Version" = $%d)`,
+		setQuery, argId, argId+1, argId+2, argId+2)
+	args = append(args, userId, itemId, version)
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = checkVersionedResult(res, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Move moves the items of the user into the list keeping their IDs. Every item and
+// the target list must belong to the user.
+func (r *ShoppingItemPostgres) Move(userId, listId int, itemIds []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = checkCurrency(tx, listId, `I."ID" = ANY($2)`, pq.Array(itemIds)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// an empty position appends the items to the end of the list
+	query := fmt.Sprintf(`UPDATE "Shopping_Item" I SET "List_id" = T."ID", "Position" = '', "Version" = I."Version" + 1, "Updated_at" = now()
+									FROM "Shopping_List" S, "Shopping_List" T
+									WHERE S."ID" = I."List_id" AND S."User_id" = $1 AND T."ID" = $2 AND T."User_id" = $1
+									AND I."ID" = ANY($3)`)
+	res, err := tx.Exec(query, userId, listId, pq.Array(itemIds))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected != int64(len(itemIds)) {
+		tx.Rollback()
+		return errors.New("unknown list or items")
+	}
+
+	if err = moveItemTags(tx, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Copy adds unchecked copies of the items of the user to the list and returns their IDs
+// in the order of itemIds.
+func (r *ShoppingItemPostgres) Copy(userId, listId int, itemIds []int) ([]int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	// copies take the estimated price only
+	if err = checkCurrency(tx.Tx, listId, `I."ID" = ANY($2) AND I."Estimated_price" <> 0`, pq.Array(itemIds)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// rows are copied one by one: RETURNING does not keep the order of a multi-row insert
+	ids := make([]int, 0, len(itemIds))
+	query := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("List_id", "Title", "Description", "Quantity", "Unit", "Category", "Estimated_price")
+									SELECT T."ID", I."Title", I."Description", I."Quantity", I."Unit", I."Category", I."Estimated_price" FROM "Shopping_Item" I
+									INNER JOIN "Shopping_List" S on I."List_id" = S."ID"
+									INNER JOIN "Shopping_List" T on T."User_id" = S."User_id"
+									WHERE S."User_id" = $1 AND T."ID" = $2 AND I."ID" = $3
+									RETURNING "ID"`)
+	for _, itemId := range itemIds {
+		var id int
+		err = tx.Get(&id, query, userId, listId, itemId)
+		if errors.Is(err, sql.ErrNoRows) {
+			tx.Rollback()
+			return nil, errors.New("unknown list or items")
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, tx.Commit()
+}
+
+// checkCurrency returns model.ErrCurrencyMismatch if an item matching the condition on I has a price
+// and belongs to a list in another currency than the target list. The condition args start at $2.
+func checkCurrency(tx *sql.Tx, targetId int, condition string, args ...interface{}) error {
+	var mismatch bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM "Shopping_Item" I
+										INNER JOIN "Shopping_List" S on I."List_id" = S."ID"
+										INNER JOIN "Shopping_List" T on T."ID" = $1
+									WHERE %s AND S."Currency" <> T."Currency" AND (I."Estimated_price" <> 0 OR I."Actual_price" <> 0))`,
+		condition)
+	if err := tx.QueryRow(query, append([]interface{}{targetId}, args...)...).Scan(&mismatch); err != nil {
+		return err
+	}
+	if mismatch {
+		return model.ErrCurrencyMismatch
+	}
+
+	return nil
+}
+
+// SetPosition places the item between its neighbour items and returns its new position.
+// A non-zero version makes the change conditional like in Update.
+func (r *ShoppingItemPostgres) SetPosition(userId, itemId int, input model.ItemPositionInput, version int) (string, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", err
+	}
+
+	// the lock of the list keeps the neighbours in place until the item is moved
+	var item struct {
+		ListId  int `db:"List_id"`
+		Version int `db:"Version"`
+	}
+	lockQuery := fmt.Sprintf(`SELECT I."List_id", I."Version" FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+									WHERE I."ID" = $1 AND L."User_id" = $2 FOR UPDATE`)
+	if err = tx.Get(&item, lockQuery, itemId, userId); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if version != 0 && item.Version != version {
+		tx.Rollback()
+		return "", model.ErrVersionMismatch
+	}
+
+	var after, before string
+	neighbourQuery := fmt.Sprintf(`SELECT I."Position" FROM "Shopping_Item" I WHERE I."List_id" = $1 AND I."ID" = $2 AND I."ID" <> $3`)
+	if input.AfterId != 0 {
+		if err = tx.Get(&after, neighbourQuery, item.ListId, input.AfterId, itemId); err != nil {
+			tx.Rollback()
+			return "", unknownNeighbour(err)
+		}
+	}
+	if input.BeforeId != 0 {
+		if err = tx.Get(&before, neighbourQuery, item.ListId, input.BeforeId, itemId); err != nil {
+			tx.Rollback()
+			return "", unknownNeighbour(err)
+		}
+	} else {
+		query := fmt.Sprintf(`SELECT COALESCE(MIN(I."Position"), '') FROM "Shopping_Item" I
+									WHERE I."List_id" = $1 AND I."Position" > $2 AND I."ID" <> $3`)
+		if err = tx.Get(&before, query, item.ListId, after, itemId); err != nil {
+			tx.Rollback()
+			return "", err
+		}
+	}
+	if input.AfterId == 0 {
+		query := fmt.Sprintf(`SELECT COALESCE(MAX(I."Position"), '') FROM "Shopping_Item" I
+									WHERE I."List_id" = $1 AND I."Position" < $2 AND I."ID" <> $3`)
+		if err = tx.Get(&after, query, item.ListId, before, itemId); err != nil {
+			tx.Rollback()
+			return "", err
+		}
+	}
+
+	position, err := model.RankBetween(after, before)
+	if errors.Is(err, model.ErrRankOrder) {
+		tx.Rollback()
+		return "", errors.New("neighbour items are not in order")
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	query := fmt.Sprintf(`UPDATE "Shopping_Item" I SET "Position" = $1, "Version" = I."Version" + 1, "Updated_at" = now() WHERE I."ID" = $2`)
+	if _, err = tx.Exec(query, position, itemId); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	return position, tx.Commit()
+}
+
+func unknownNeighbour(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("unknown neighbour item")
+	}
 	return err
 }
 
-func (r *ShoppingItemPostgres) Update(userId, itemId int, input model.UpdateItemInput) error {
+// itemSetQuery returns the SET clause of an item update with the changed fields
+// and an incremented version, and the arguments it references starting from $1.
+func itemSetQuery(input model.UpdateItemInput) (string, []interface{}) {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -77,13 +318,196 @@ func (r *ShoppingItemPostgres) Update(userId, itemId int, input model.UpdateItem
 		argId++
 	}
 
-	setQuery := strings.Join(setValues, ", ")
+	if input.Quantity != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Quantity"=$%d`, argId))
+		args = append(args, *input.Quantity)
+		argId++
+	}
 
-	query := fmt.Sprintf(`UPDATE "Shopping_Item" I SET %s FROM "Shopping_List" L
-									WHERE L."ID" = I."List_id" AND L."User_id" = $%d AND I."ID" = $%d`,
+	if input.Unit != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Unit"=$%d`, argId))
+		args = append(args, *input.Unit)
+		argId++
+	}
+
+	if input.Category != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Category"=$%d`, argId))
+		args = append(args, *input.Category)
+		argId++
+	}
+
+	if input.EstimatedPrice != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Estimated_price"=$%d`, argId))
+		args = append(args, *input.EstimatedPrice)
+		argId++
+	}
+
+	if input.ActualPrice != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Actual_price"=$%d`, argId))
+		args = append(args, *input.ActualPrice)
+		argId++
+	}
+
+	if input.Clocks != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Clocks"=I."Clocks" || $%d::jsonb`, argId))
+		args = append(args, input.Clocks)
+		argId++
+	}
+
+	setValues = append(setValues, `"Version"=I."Version"+1`, `"Updated_at"=now()`)
+
+	return strings.Join(setValues, ", "), args
+}
+
+// Bulk executes the operations over items of the list in one transaction and reports
+// the outcome for every item. The list version is incremented once if anything changed.
+func (r *ShoppingItemPostgres) Bulk(userId, listId int, ops []model.BulkItemOperation) ([]model.BulkItemResult, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	lockQuery := fmt.Sprintf(`SELECT L."ID" FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID" = $2 FOR UPDATE`)
+	if err = tx.QueryRow(lockQuery, userId, listId).Scan(&id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	results := make([]model.BulkItemResult, 0)
+	changed := false
+	for _, op := range ops {
+		var applied []int
+		requested := op.Ids
+
+		switch op.Op {
+		case model.BulkOpCheckAll, model.BulkOpUncheckAll:
+			query := fmt.Sprintf(`SELECT I."ID" FROM "Shopping_Item" I WHERE I."List_id" = $1 AND I."Checked" <> $2 ORDER BY I."ID"`)
+			if err = tx.Select(&requested, query, listId, *op.Patch.Checked); err == nil {
+				applied, err = updateListItems(tx, listId, requested, *op.Patch)
+			}
+		case model.BulkOpDeleteChecked:
+			query := fmt.Sprintf(`DELETE FROM "Shopping_Item" I WHERE I."List_id" = $1 AND I."Checked" RETURNING I."ID"`)
+			err = tx.Select(&applied, query, listId)
+			requested = applied
+		case model.BulkOpDelete:
+			query := fmt.Sprintf(`DELETE FROM "Shopping_Item" I WHERE I."List_id" = $1 AND I."ID" = ANY($2) RETURNING I."ID"`)
+			err = tx.Select(&applied, query, listId, pq.Array(op.Ids))
+		case model.BulkOpUpdate:
+			applied, err = updateListItems(tx, listId, op.Ids, *op.Patch)
+		case model.BulkOpCreate:
+			query := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("Title", "List_id", "Description", "Quantity", "Unit", "Category",
+										"Estimated_price", "Actual_price")
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING "ID"`)
+			for _, item := range op.Items {
+				if err = tx.QueryRow(query, item.Title, listId, item.Description, item.Quantity, item.Unit, item.Category,
+					item.EstimatedPrice, item.ActualPrice).Scan(&id); err != nil {
+					break
+				}
+				applied = append(applied, id)
+			}
+			requested = applied
+		default:
+			err = errors.New("unknown operation " + op.Op)
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		changed = changed || len(applied) > 0
+		results = append(results, reportBulkItems(op.Op, requested, applied)...)
+	}
+
+	if changed {
+		query := fmt.Sprintf(`UPDATE "Shopping_List" L SET "Version" = L."Version" + 1, "Updated_at" = now() WHERE L."ID" = $1`)
+		if _, err = tx.Exec(query, listId); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	return results, tx.Commit()
+}
+
+// AddAll applies the changes to items of the list and creates the items in it in one transaction,
+// and returns the IDs of the created items. A change of an item whose version differs rolls
+// everything back with model.ErrVersionMismatch.
+func (r *ShoppingItemPostgres) AddAll(userId, listId int, changes []model.ItemUpdate, items []model.ShoppingItem) ([]int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		setQuery, args := itemSetQuery(change.Input)
+		argId := len(args) + 1
+
+		query := fmt.Sprintf(`UPDATE "Shopping_Item" I SET %s FROM "Shopping_List" L
+									WHERE L."ID" = I."List_id" AND L."User_id" = $%d AND L."ID" = $%d AND I."ID" = $%d AND I."Version" = $%d`,
+			setQuery, argId, argId+1, argId+2, argId+3)
+		args = append(args, userId, listId, change.Id, change.Version)
+
+		res, err := tx.Exec(query, args...)
+		if err == nil {
+			err = checkVersionedResult(res, change.Version)
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	ids := make([]int, 0, len(items))
+	query := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("Title", "List_id", "Description", "Quantity", "Unit", "Category",
+										"Estimated_price", "Actual_price")
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING "ID"`)
+	for _, item := range items {
+		var id int
+		if err = tx.Get(&id, query, item.Title, listId, item.Description, item.Quantity, item.Unit, item.Category,
+			item.EstimatedPrice, item.ActualPrice); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, tx.Commit()
+}
+
+// updateListItems applies the input to the items of the list and returns the IDs of the changed ones.
+func updateListItems(tx *sqlx.Tx, listId int, itemIds []int, input model.UpdateItemInput) ([]int, error) {
+	var ids []int
+	if len(itemIds) == 0 {
+		return ids, nil
+	}
+
+	setQuery, args := itemSetQuery(input)
+	argId := len(args) + 1
+
+	query := fmt.Sprintf(`UPDATE "Shopping_Item" I SET %s WHERE I."List_id" = $%d AND I."ID" = ANY($%d) RETURNING I."ID"`,
 		setQuery, argId, argId+1)
-	args = append(args, userId, itemId)
+	args = append(args, listId, pq.Array(itemIds))
+	err := tx.Select(&ids, query, args...)
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	return ids, err
+}
+
+// reportBulkItems reports the requested items as applied or, when the operation did not reach them, not found.
+func reportBulkItems(op string, requested, applied []int) []model.BulkItemResult {
+	reached := make(map[int]bool, len(applied))
+	for _, id := range applied {
+		reached[id] = true
+	}
+
+	results := make([]model.BulkItemResult, 0, len(requested))
+	for _, id := range requested {
+		status := model.BulkStatusApplied
+		if !reached[id] {
+			status = model.BulkStatusNotFound
+		}
+		results = append(results, model.BulkItemResult{Op: op, Id: id, Status: status})
+	}
+
+	return results
 }
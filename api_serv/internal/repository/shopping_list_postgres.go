@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"strings"
 )
 
+const listColumns = `L."ID", L."Title", L."Description", L."Archived", L."Pinned", L."Position", L."Store_id", L."Budget", L."Currency", L."Version"`
+
 type ShoppingListPostgres struct {
 	db *sqlx.DB
 }
@@ -18,18 +23,23 @@ func NewShoppingListPostgres(db *sqlx.DB) *ShoppingListPostgres {
 func (r *ShoppingListPostgres) Create(userId int, list model.ShoppingList) (int, error) {
 	var id int
 
-	createListQuery := fmt.Sprintf(`INSERT INTO "Shopping_List" ("Title", "User_id","Description") VALUES ($1, $2, $3) RETURNING "ID"`)
-	row := r.db.QueryRow(createListQuery, list.Title, userId, list.Description)
+	createListQuery := fmt.Sprintf(`INSERT INTO "Shopping_List" ("Title", "User_id", "Description", "Budget", "Currency", "Position")
+									SELECT $1, $2, $3, $4, $5, COALESCE(MAX(L."Position"), 0) + 1 FROM "Shopping_List" L WHERE L."User_id" = $2
+									RETURNING "ID"`)
+	row := r.db.QueryRow(createListQuery, list.Title, userId, list.Description, list.Budget, list.Currency)
 	err := row.Scan(&id)
 
 	return id, err
 }
 
-func (r *ShoppingListPostgres) GetAll(userId int) ([]model.ShoppingList, error) {
+// GetAll returns pinned lists first, then the rest in user-defined order.
+// A nil archived returns both archived and active lists.
+func (r *ShoppingListPostgres) GetAll(userId int, archived *bool) ([]model.ShoppingList, error) {
 	var lists []model.ShoppingList
 
-	query := fmt.Sprintf(`SELECT L."ID", L."Title", L."Description" FROM "Shopping_List" L WHERE L."User_id" = $1`)
-	err := r.db.Select(&lists, query, userId)
+	query := fmt.Sprintf(`SELECT %s FROM "Shopping_List" L WHERE L."User_id" = $1 AND ($2::boolean IS NULL OR L."Archived" = $2)
+									ORDER BY L."Pinned" DESC, L."Position", L."ID"`, listColumns)
+	err := r.db.Select(&lists, query, userId, archived)
 
 	return lists, err
 }
@@ -37,13 +47,15 @@ func (r *ShoppingListPostgres) GetAll(userId int) ([]model.ShoppingList, error)
 func (r *ShoppingListPostgres) GetById(userId, listId int) (model.ShoppingList, error) {
 	var list model.ShoppingList
 
-	query := fmt.Sprintf(`SELECT L."ID", L."Title", L."Description" FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID"=$2`)
+	query := fmt.Sprintf(`SELECT %s FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID"=$2`, listColumns)
 	err := r.db.Get(&list, query, userId, listId)
 
 	return list, err
 }
 
-func (r *ShoppingListPostgres) Update(userId, listId int, input model.UpdateListInput) error {
+// Update changes the list and increments its version. A non-zero version makes the update
+// conditional: model.ErrVersionMismatch is returned when the stored version differs.
+func (r *ShoppingListPostgres) Update(userId, listId int, input model.UpdateListInput, version int) error {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -58,20 +70,249 @@ func (r *ShoppingListPostgres) Update(userId, listId int, input model.UpdateList
 		args = append(args, *input.Description)
 		argId++
 	}
+	if input.Pinned != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Pinned"=$%d`, argId))
+		args = append(args, *input.Pinned)
+		argId++
+	}
+	if input.StoreId != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Store_id"=NULLIF($%d, 0)`, argId))
+		args = append(args, *input.StoreId)
+		argId++
+	}
+	if input.Budget != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Budget"=$%d`, argId))
+		args = append(args, *input.Budget)
+		argId++
+	}
+	if input.Currency != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Currency"=$%d`, argId))
+		args = append(args, *input.Currency)
+		argId++
+	}
+	if input.Archived != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Archived"=$%d`, argId))
+		args = append(args, *input.Archived)
+		argId++
+	}
 
+	setValues = append(setValues, `"Version"=L."Version"+1`, `"Updated_at"=now()`)
 	setQuery := strings.Join(setValues, ", ")
 
-	query := fmt.Sprintf(`UPDATE "Shopping_List" L SET %s WHERE L."User_id" = $%d AND L."ID" = $%d`,
-		setQuery, argId, argId+1)
-	args = append(args, userId, listId)
+	query := fmt.Sprintf(`UPDATE "Shopping_List" L SET %s WHERE L."User_id" = $%d AND L."ID" = $%d
+									AND ($%d = 0 OR L."Version" = $%d)`,
+		setQuery, argId, argId+1, argId+2, argId+2)
+	args = append(args, userId, listId, version)
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	return checkVersionedResult(res, version)
+}
+
+func (r *ShoppingListPostgres) Delete(userId, listId int, version int) error {
+	query := fmt.Sprintf(`DELETE FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID" = $2 AND ($3 = 0 OR L."Version" = $3)`)
+	res, err := r.db.Exec(query, userId, listId, version)
+	if err != nil {
+		return err
+	}
+
+	return checkVersionedResult(res, version)
+}
+
+// Reorder sets list positions to the order of ids. All ids must belong to the user.
+func (r *ShoppingListPostgres) Reorder(userId int, ids []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE "Shopping_List" L SET "Position" = O."Position", "Version" = L."Version" + 1, "Updated_at" = now()
+									FROM unnest($2::bigint[]) WITH ORDINALITY AS O("ID", "Position")
+									WHERE L."ID" = O."ID" AND L."User_id" = $1`)
+	res, err := tx.Exec(query, userId, pq.Array(ids))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected != int64(len(ids)) {
+		tx.Rollback()
+		return errors.New("order contains unknown lists")
+	}
+
+	return tx.Commit()
+}
+
+// GetTotals sums the prices of the items of the list.
+func (r *ShoppingListPostgres) GetTotals(listId int) (model.ListTotals, error) {
+	var totals model.ListTotals
+
+	query := fmt.Sprintf(`SELECT COALESCE(sum(P."Expected"), 0) AS "Estimated",
+										COALESCE(sum(P."Paid") FILTER (WHERE P."Checked"), 0) AS "Spent",
+										COALESCE(sum(P."Expected") FILTER (WHERE NOT P."Checked"), 0) AS "Pending"
+									FROM (SELECT I."Checked", COALESCE(NULLIF(I."Estimated_price", 0), I."Actual_price") AS "Expected",
+											COALESCE(NULLIF(I."Actual_price", 0), I."Estimated_price") AS "Paid"
+										FROM "Shopping_Item" I WHERE I."List_id" = $1) P`)
+	err := r.db.Get(&totals, query, listId)
+
+	return totals, err
+}
+
+// Duplicate creates a copy of the list with its items, only the unchecked ones if uncheckedOnly is set.
+func (r *ShoppingListPostgres) Duplicate(userId, listId int, title string, uncheckedOnly bool) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	createListQuery := fmt.Sprintf(`INSERT INTO "Shopping_List" ("Title", "User_id", "Description", "Budget", "Currency", "Position")
+									SELECT COALESCE(NULLIF($3, ''), L."Title"), L."User_id", L."Description", L."Budget", L."Currency",
+										(SELECT COALESCE(MAX(P."Position"), 0) + 1 FROM "Shopping_List" P WHERE P."User_id" = $1)
+									FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID" = $2
+									RETURNING "ID"`)
+	row := tx.QueryRow(createListQuery, userId, listId, title)
+	if err = row.Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	copyItemsQuery := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("List_id", "Title", "Description", "Checked", "Quantity", "Unit", "Category",
+										"Estimated_price", "Actual_price", "Position")
+									SELECT $1, I."Title", I."Description", I."Checked", I."Quantity", I."Unit", I."Category",
+										I."Estimated_price", I."Actual_price", I."Position" FROM "Shopping_Item" I
+									WHERE I."List_id" = $2 AND NOT ($3 AND I."Checked") ORDER BY I."Position", I."ID"`)
+	if _, err = tx.Exec(copyItemsQuery, id, listId, uncheckedOnly); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// Merge moves the items of lists ids into the target list and deletes the source lists.
+// Items with the same title, ignoring case, and the same unit are consolidated into one that keeps the
+// oldest item of the target list if there is one, sums the quantities and stays
+// unchecked unless all of them were checked. Items of the source lists are appended to the target list.
+func (r *ShoppingListPostgres) Merge(userId, targetId int, ids []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	all := append([]int{targetId}, ids...)
+	var locked int
+	lockQuery := fmt.Sprintf(`SELECT count(*) FROM (SELECT L."ID" FROM "Shopping_List" L
+										WHERE L."User_id" = $1 AND L."ID" = ANY($2) FOR UPDATE) S`)
+	if err = tx.QueryRow(lockQuery, userId, pq.Array(all)).Scan(&locked); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if locked != len(all) {
+		tx.Rollback()
+		return errors.New("merge contains unknown lists")
+	}
+	// prices are summed, so they must be in the currency of the target list
+	if err = checkCurrency(tx, targetId, `I."List_id" = ANY($2)`, pq.Array(ids)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	consolidateQuery := fmt.Sprintf(`WITH G AS (
+										SELECT DISTINCT ON (lower(btrim(I."Title")), I."Unit") I."ID",
+											sum(I."Quantity") OVER W AS "Quantity", bool_and(I."Checked") OVER W AS "Checked",
+											sum(I."Estimated_price") OVER W AS "Estimated_price", sum(I."Actual_price") OVER W AS "Actual_price"
+										FROM "Shopping_Item" I WHERE I."List_id" = ANY($2)
+										WINDOW W AS (PARTITION BY lower(btrim(I."Title")), I."Unit")
+										ORDER BY lower(btrim(I."Title")), I."Unit", I."List_id" <> $1, I."ID"),
+									D AS (
+										DELETE FROM "Shopping_Item" I WHERE I."List_id" = $1 AND I."ID" NOT IN (SELECT G."ID" FROM G))
+									UPDATE "Shopping_Item" I SET "List_id" = $1, "Quantity" = G."Quantity", "Checked" = G."Checked",
+										"Estimated_price" = G."Estimated_price", "Actual_price" = G."Actual_price",
+										"Position" = CASE WHEN I."List_id" = $1 THEN I."Position" ELSE '' END,
+										"Version" = I."Version" + 1, "Updated_at" = now()
+									FROM G WHERE I."ID" = G."ID"
+										AND (I."List_id" <> $1 OR I."Quantity" <> G."Quantity" OR I."Checked" <> G."Checked"
+											OR I."Estimated_price" <> G."Estimated_price" OR I."Actual_price" <> G."Actual_price")`)
+	if _, err = tx.Exec(consolidateQuery, targetId, pq.Array(all)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = moveItemTags(tx, targetId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// the remaining items of the source lists are duplicates and go with them
+	deleteListsQuery := fmt.Sprintf(`DELETE FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID" = ANY($2)`)
+	if _, err = tx.Exec(deleteListsQuery, userId, pq.Array(ids)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Split creates a new list and moves the given items of the list into it.
+func (r *ShoppingListPostgres) Split(userId, listId int, title string, itemIds []int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	// prices of the moved items are in the currency of the list
+	createListQuery := fmt.Sprintf(`INSERT INTO "Shopping_List" ("Title", "User_id", "Description", "Currency", "Position")
+									SELECT $3, L."User_id", '', L."Currency",
+										(SELECT COALESCE(MAX(P."Position"), 0) + 1 FROM "Shopping_List" P WHERE P."User_id" = $1)
+									FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID" = $2
+									RETURNING "ID"`)
+	row := tx.QueryRow(createListQuery, userId, listId, title)
+	if err = row.Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	moveItemsQuery := fmt.Sprintf(`UPDATE "Shopping_Item" I SET "List_id" = $1, "Version" = I."Version" + 1, "Updated_at" = now()
+									WHERE I."List_id" = $2 AND I."ID" = ANY($3)`)
+	res, err := tx.Exec(moveItemsQuery, id, listId, pq.Array(itemIds))
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if affected != int64(len(itemIds)) {
+		tx.Rollback()
+		return 0, errors.New("split contains unknown items")
+	}
+
+	if err = moveItemTags(tx, id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
 }
 
-func (r *ShoppingListPostgres) Delete(userId, listId int) error {
-	query := fmt.Sprintf(`DELETE FROM "Shopping_List" L WHERE L."User_id" = $1 AND L."ID" = $2`)
-	_, err := r.db.Exec(query, userId, listId)
+// moveItemTags moves the membership tags of items that were moved into the list along with them.
+func moveItemTags(tx *sql.Tx, listId int) error {
+	query := fmt.Sprintf(`UPDATE "Item_Tag" T SET "List_id" = I."List_id" FROM "Shopping_Item" I
+									WHERE T."Item_id" = I."ID" AND I."List_id" = $1 AND T."List_id" <> $1`)
+	_, err := tx.Exec(query, listId)
 
 	return err
 }
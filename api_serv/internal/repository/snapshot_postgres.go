@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"sort"
+)
+
+type SnapshotPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSnapshotPostgres(db *sqlx.DB) *SnapshotPostgres {
+	return &SnapshotPostgres{db: db}
+}
+
+func (r *SnapshotPostgres) Create(userId, listId int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var snapshotId int
+	createSnapshotQuery := fmt.Sprintf(`INSERT INTO "List_Snapshot" ("List_id", "Title", "Description")
+									SELECT L."ID", L."Title", L."Description" FROM "Shopping_List" L
+									WHERE L."User_id" = $1 AND L."ID" = $2 RETURNING "ID"`)
+	row := tx.QueryRow(createSnapshotQuery, userId, listId)
+	if err = row.Scan(&snapshotId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	copyItemsQuery := fmt.Sprintf(`INSERT INTO "Snapshot_Item" ("Snapshot_id", "Item_id", "Title", "Description", "Checked",
+										"Quantity", "Unit", "Category", "Estimated_price", "Actual_price")
+									SELECT $1, I."ID", I."Title", I."Description", I."Checked",
+										I."Quantity", I."Unit", I."Category", I."Estimated_price", I."Actual_price" FROM "Shopping_Item" I
+									WHERE I."List_id" = $2`)
+	if _, err = tx.Exec(copyItemsQuery, snapshotId, listId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return snapshotId, tx.Commit()
+}
+
+func (r *SnapshotPostgres) GetAll(userId, listId int) ([]model.ListSnapshot, error) {
+	var snapshots []model.ListSnapshot
+	query := fmt.Sprintf(`SELECT S."ID", S."List_id", S."Title", S."Description", S."Created_at" FROM "List_Snapshot" S
+									INNER JOIN "Shopping_List" L on S."List_id" = L."ID"
+									WHERE S."List_id" = $1 AND L."User_id" = $2 ORDER BY S."Created_at" DESC`)
+	err := r.db.Select(&snapshots, query, listId, userId)
+
+	return snapshots, err
+}
+
+func (r *SnapshotPostgres) GetById(userId, snapshotId int) (model.ListSnapshot, error) {
+	var snapshot model.ListSnapshot
+	query := fmt.Sprintf(`SELECT S."ID", S."List_id", S."Title", S."Description", S."Created_at" FROM "List_Snapshot" S
+									INNER JOIN "Shopping_List" L on S."List_id" = L."ID"
+									WHERE S."ID" = $1 AND L."User_id" = $2`)
+	if err := r.db.Get(&snapshot, query, snapshotId, userId); err != nil {
+		return snapshot, err
+	}
+
+	itemsQuery := fmt.Sprintf(`SELECT SI."Item_id" AS "ID", SI."Title", SI."Description", SI."Checked",
+										SI."Quantity", SI."Unit", SI."Category", SI."Estimated_price", SI."Actual_price" FROM "Snapshot_Item" SI
+									WHERE SI."Snapshot_id" = $1 ORDER BY SI."Item_id"`)
+	err := r.db.Select(&snapshot.Items, itemsQuery, snapshotId)
+
+	return snapshot, err
+}
+
+// Restore brings the list back to the state stored in the snapshot. Items that still exist
+// keep their IDs, items moved to other lists of the user are moved back, deleted ones are
+// recreated and items added after the snapshot are removed.
+func (r *SnapshotPostgres) Restore(userId, snapshotId int) (model.SnapshotRestore, error) {
+	restore := model.SnapshotRestore{MovedBack: make([]int, 0)}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return restore, err
+	}
+
+	var listId int
+	restoreListQuery := fmt.Sprintf(`UPDATE "Shopping_List" L SET "Title" = S."Title", "Description" = S."Description",
+									"Version" = L."Version" + 1, "Updated_at" = now()
+									FROM "List_Snapshot" S WHERE S."List_id" = L."ID" AND S."ID" = $1 AND L."User_id" = $2
+									RETURNING L."ID"`)
+	row := tx.QueryRow(restoreListQuery, snapshotId, userId)
+	if err = row.Scan(&listId); err != nil {
+		tx.Rollback()
+		return restore, err
+	}
+	restore.Id = listId
+
+	// an empty position appends the items to the end of the list
+	moveBackQuery := fmt.Sprintf(`UPDATE "Shopping_Item" I SET "List_id" = $1, "Position" = '', "Version" = I."Version" + 1, "Updated_at" = now()
+									FROM "Snapshot_Item" SI, "Shopping_List" L
+									WHERE SI."Snapshot_id" = $2 AND SI."Item_id" = I."ID" AND I."List_id" <> $1
+									AND L."ID" = I."List_id" AND L."User_id" = $3
+									RETURNING I."ID"`)
+	rows, err := tx.Query(moveBackQuery, listId, snapshotId, userId)
+	if err != nil {
+		tx.Rollback()
+		return restore, err
+	}
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return restore, err
+		}
+		restore.MovedBack = append(restore.MovedBack, id)
+	}
+	if err = rows.Err(); err != nil {
+		tx.Rollback()
+		return restore, err
+	}
+	sort.Ints(restore.MovedBack)
+
+	if err = moveItemTags(tx, listId); err != nil {
+		tx.Rollback()
+		return restore, err
+	}
+
+	deleteItemsQuery := fmt.Sprintf(`DELETE FROM "Shopping_Item" I WHERE I."List_id" = $1
+									AND I."ID" NOT IN (SELECT SI."Item_id" FROM "Snapshot_Item" SI WHERE SI."Snapshot_id" = $2)`)
+	if _, err = tx.Exec(deleteItemsQuery, listId, snapshotId); err != nil {
+		tx.Rollback()
+		return restore, err
+	}
+
+	restoreItemsQuery := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("ID", "List_id", "Title", "Description", "Checked",
+										"Quantity", "Unit", "Category", "Estimated_price", "Actual_price")
+									SELECT SI."Item_id", $1, SI."Title", SI."Description", SI."Checked",
+										SI."Quantity", SI."Unit", SI."Category", SI."Estimated_price", SI."Actual_price" FROM "Snapshot_Item" SI
+									WHERE SI."Snapshot_id" = $2
+									ON CONFLICT ("ID") DO UPDATE SET "Title" = EXCLUDED."Title", "Description" = EXCLUDED."Description",
+									"Checked" = EXCLUDED."Checked", "Quantity" = EXCLUDED."Quantity", "Unit" = EXCLUDED."Unit",
+									"Category" = EXCLUDED."Category", "Estimated_price" = EXCLUDED."Estimated_price",
+									"Actual_price" = EXCLUDED."Actual_price", "Version" = "Shopping_Item"."Version" + 1, "Updated_at" = now()
+									WHERE "Shopping_Item"."List_id" = EXCLUDED."List_id"`)
+	if _, err = tx.Exec(restoreItemsQuery, listId, snapshotId); err != nil {
+		tx.Rollback()
+		return restore, err
+	}
+
+	return restore, tx.Commit()
+}
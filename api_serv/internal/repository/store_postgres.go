@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"strings"
+)
+
+type StorePostgres struct {
+	db *sqlx.DB
+}
+
+func NewStorePostgres(db *sqlx.DB) *StorePostgres {
+	return &StorePostgres{db: db}
+}
+
+func (r *StorePostgres) Create(userId int, store model.Store) (int, error) {
+	var id int
+	query := fmt.Sprintf(`INSERT INTO "Store" ("User_id", "Title", "Aisles") VALUES ($1, $2, $3) RETURNING "ID"`)
+	row := r.db.QueryRow(query, userId, store.Title, store.Aisles)
+	err := row.Scan(&id)
+
+	return id, err
+}
+
+func (r *StorePostgres) GetAll(userId int) ([]model.Store, error) {
+	var stores []model.Store
+	query := fmt.Sprintf(`SELECT S."ID", S."Title", S."Aisles" FROM "Store" S WHERE S."User_id" = $1 ORDER BY S."Title"`)
+	err := r.db.Select(&stores, query, userId)
+
+	return stores, err
+}
+
+func (r *StorePostgres) GetById(userId, storeId int) (model.Store, error) {
+	var store model.Store
+	query := fmt.Sprintf(`SELECT S."ID", S."Title", S."Aisles" FROM "Store" S WHERE S."User_id" = $1 AND S."ID" = $2`)
+	err := r.db.Get(&store, query, userId, storeId)
+
+	return store, err
+}
+
+func (r *StorePostgres) Update(userId, storeId int, input model.UpdateStoreInput) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Title != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Title"=$%d`, argId))
+		args = append(args, *input.Title)
+		argId++
+	}
+	if input.Aisles != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Aisles"=$%d`, argId))
+		args = append(args, pq.Array(*input.Aisles))
+		argId++
+	}
+
+	setQuery := strings.Join(setValues, ", ")
+	query := fmt.Sprintf(`UPDATE "Store" S SET %s WHERE S."User_id" = $%d AND S."ID" = $%d`, setQuery, argId, argId+1)
+	args = append(args, userId, storeId)
+	_, err := r.db.Exec(query, args...)
+
+	return err
+}
+
+func (r *StorePostgres) Delete(userId, storeId int) error {
+	query := fmt.Sprintf(`DELETE FROM "Store" S WHERE S."User_id" = $1 AND S."ID" = $2`)
+	_, err := r.db.Exec(query, userId, storeId)
+
+	return err
+}
+
+// GetRanks returns the learned positions at which products are checked off in the store by normalized title.
+func (r *StorePostgres) GetRanks(storeId int) (map[string]float64, error) {
+	var rows []struct {
+		Title string  `db:"Title"`
+		Rank  float64 `db:"Rank"`
+	}
+	query := fmt.Sprintf(`SELECT R."Title", R."Rank" FROM "Store_Rank" R WHERE R."Store_id" = $1`)
+	if err := r.db.Select(&rows, query, storeId); err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		ranks[row.Title] = row.Rank
+	}
+
+	return ranks, nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+type SyncPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSyncPostgres(db *sqlx.DB) *SyncPostgres {
+	return &SyncPostgres{db: db}
+}
+
+// GetChanges collects lists and items changed by transactions not older than the since cursor.
+// The cursor is the oldest transaction still running when the snapshot of the repeatable read
+// transaction was taken: changes committed later by such transactions are returned by the next pull,
+// so a change is never skipped but may be returned twice.
+func (r *SyncPostgres) GetChanges(userId, since int) (model.SyncChanges, error) {
+	changes := model.SyncChanges{
+		Lists:   make([]model.ShoppingList, 0),
+		Items:   make([]model.SyncItem, 0),
+		Deleted: make([]model.Tombstone, 0),
+	}
+
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return changes, err
+	}
+	defer tx.Rollback()
+
+	cursorQuery := fmt.Sprintf(`SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint`)
+	if err = tx.Get(&changes.Cursor, cursorQuery); err != nil {
+		return changes, err
+	}
+
+	listsQuery := fmt.Sprintf(`SELECT %s FROM "Shopping_List" L
+									WHERE L."User_id" = $1 AND L."ID" IN (SELECT C."Entity_id" FROM "Change_Log" C
+										WHERE C."User_id" = $1 AND C."Xid" >= $2::text::xid8 AND C."Entity" = 'list')
+									ORDER BY L."ID"`, listColumns)
+	if err = tx.Select(&changes.Lists, listsQuery, userId, since); err != nil {
+		return changes, err
+	}
+
+	itemsQuery := fmt.Sprintf(`SELECT I."ID", I."List_id", I."Title", I."Description", I."Checked", I."Quantity", I."Unit", I."Category", I."Estimated_price", I."Actual_price", I."Position", I."Version", I."Clocks",
+										ARRAY(SELECT T."Tag" FROM "Item_Tag" T WHERE T."Item_id" = I."ID" AND NOT T."Removed" ORDER BY T."Tag") AS "Tags"
+									FROM "Shopping_Item" I
+									INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+									WHERE L."User_id" = $1 AND I."ID" IN (SELECT C."Entity_id" FROM "Change_Log" C
+										WHERE C."User_id" = $1 AND C."Xid" >= $2::text::xid8 AND C."Entity" = 'item')
+									ORDER BY I."ID"`)
+	if err = tx.Select(&changes.Items, itemsQuery, userId, since); err != nil {
+		return changes, err
+	}
+
+	tombstonesQuery := fmt.Sprintf(`SELECT DISTINCT ON (C."Entity", C."Entity_id") C."Entity", C."Entity_id", C."List_id" FROM "Change_Log" C
+									WHERE C."User_id" = $1 AND C."Xid" >= $2::text::xid8 AND C."Deleted"
+									AND NOT EXISTS (SELECT 1 FROM "Shopping_List" L WHERE C."Entity" = 'list' AND L."ID" = C."Entity_id" AND L."User_id" = $1)
+									AND NOT EXISTS (SELECT 1 FROM "Shopping_Item" I INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+										WHERE C."Entity" = 'item' AND I."ID" = C."Entity_id" AND L."User_id" = $1)
+									ORDER BY C."Entity", C."Entity_id", C."ID" DESC`)
+	if err = tx.Select(&changes.Deleted, tombstonesQuery, userId, since); err != nil {
+		return changes, err
+	}
+
+	return changes, tx.Commit()
+}
+
+func (r *SyncPostgres) GetClientId(userId int, clientId string) (string, int, error) {
+	var mapping struct {
+		Entity   string `db:"Entity"`
+		EntityId int    `db:"Entity_id"`
+	}
+	query := fmt.Sprintf(`SELECT S."Entity", S."Entity_id" FROM "Sync_Client_Id" S WHERE S."User_id" = $1 AND S."Client_id" = $2`)
+	err := r.db.Get(&mapping, query, userId, clientId)
+
+	return mapping.Entity, mapping.EntityId, err
+}
+
+// SaveClientId maps the client ID to an object that already exists. A client ID that is already mapped keeps its object.
+func (r *SyncPostgres) SaveClientId(userId int, clientId, entity string, entityId int) error {
+	query := fmt.Sprintf(`INSERT INTO "Sync_Client_Id" ("User_id", "Client_id", "Entity", "Entity_id") VALUES ($1, $2, $3, $4)
+									ON CONFLICT DO NOTHING`)
+	_, err := r.db.Exec(query, userId, clientId, entity, entityId)
+
+	return err
+}
+
+// CreateList creates the list of a mutation and maps the client ID to it in one transaction.
+// It returns the entity and the ID the client ID is mapped to, which are not the created list
+// when the client ID has already been used.
+func (r *SyncPostgres) CreateList(userId int, clientId string, list model.ShoppingList) (string, int, error) {
+	return r.create(userId, clientId, model.SyncEntityList, func(tx *sqlx.Tx) (int, error) {
+		var id int
+		query := fmt.Sprintf(`INSERT INTO "Shopping_List" ("Title", "User_id", "Description", "Budget", "Currency", "Position")
+									SELECT $1, $2, $3, $4, $5, COALESCE(MAX(L."Position"), 0) + 1 FROM "Shopping_List" L WHERE L."User_id" = $2
+									RETURNING "ID"`)
+		err := tx.Get(&id, query, list.Title, userId, list.Description, list.Budget, list.Currency)
+
+		return id, err
+	})
+}
+
+// CreateItem creates the item of a mutation with the field clocks and the client ID as its add tag,
+// and maps the client ID to it in one transaction. An item with an ID is inserted back under it only if
+// the user has a tombstone of that ID and it is free, otherwise it gets a new ID. It returns the entity
+// and the ID the client ID is mapped to, like CreateList.
+func (r *SyncPostgres) CreateItem(userId int, clientId string, listId int, item model.ShoppingItem,
+	clocks model.FieldClocks) (string, int, error) {
+	return r.create(userId, clientId, model.SyncEntityItem, func(tx *sqlx.Tx) (int, error) {
+		var id int
+		err := sql.ErrNoRows
+		if item.Id != 0 {
+			recreateQuery := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("ID", "List_id", "Title", "Description", "Checked", "Quantity", "Unit", "Category",
+										"Estimated_price", "Actual_price", "Clocks")
+									SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb
+									WHERE EXISTS (SELECT 1 FROM "Change_Log" C
+										WHERE C."User_id" = $12 AND C."Entity" = 'item' AND C."Entity_id" = $1 AND C."Deleted")
+									ON CONFLICT ("ID") DO NOTHING RETURNING "ID"`)
+			err = tx.Get(&id, recreateQuery, item.Id, listId, item.Title, item.Description, item.Checked, item.Quantity,
+				item.Unit, item.Category, item.EstimatedPrice, item.ActualPrice, clocks, userId)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			createQuery := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("List_id", "Title", "Description", "Checked", "Quantity", "Unit", "Category",
+										"Estimated_price", "Actual_price", "Clocks")
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::jsonb) RETURNING "ID"`)
+			err = tx.Get(&id, createQuery, listId, item.Title, item.Description, item.Checked, item.Quantity, item.Unit,
+				item.Category, item.EstimatedPrice, item.ActualPrice, clocks)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		tagQuery := fmt.Sprintf(`INSERT INTO "Item_Tag" ("Item_id", "List_id", "Tag") VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`)
+		_, err = tx.Exec(tagQuery, id, listId, clientId)
+
+		return id, err
+	})
+}
+
+// create maps the client ID to the object made by insert in one transaction. The mapping is inserted
+// first: a concurrent push of the same mutation waits for it and gets the mapping instead of creating
+// a second object.
+func (r *SyncPostgres) create(userId int, clientId, entity string, insert func(tx *sqlx.Tx) (int, error)) (string, int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var claimed bool
+	claimQuery := fmt.Sprintf(`INSERT INTO "Sync_Client_Id" ("User_id", "Client_id", "Entity", "Entity_id") VALUES ($1, $2, $3, 0)
+									ON CONFLICT ("User_id", "Client_id") DO NOTHING RETURNING true`)
+	err = tx.Get(&claimed, claimQuery, userId, clientId, entity)
+	if errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return r.GetClientId(userId, clientId)
+	}
+	if err != nil {
+		tx.Rollback()
+		return "", 0, err
+	}
+
+	id, err := insert(tx)
+	if err != nil {
+		tx.Rollback()
+		return "", 0, err
+	}
+
+	mapQuery := fmt.Sprintf(`UPDATE "Sync_Client_Id" SET "Entity_id" = $3 WHERE "User_id" = $1 AND "Client_id" = $2`)
+	if _, err = tx.Exec(mapQuery, userId, clientId, id); err != nil {
+		tx.Rollback()
+		return "", 0, err
+	}
+
+	return entity, id, tx.Commit()
+}
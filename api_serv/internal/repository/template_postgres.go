@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+type TemplatePostgres struct {
+	db *sqlx.DB
+}
+
+func NewTemplatePostgres(db *sqlx.DB) *TemplatePostgres {
+	return &TemplatePostgres{db: db}
+}
+
+func (r *TemplatePostgres) Create(userId int, template model.ListTemplate) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	createTemplateQuery := fmt.Sprintf(`INSERT INTO "List_Template" ("User_id", "Title", "Description") VALUES ($1, $2, $3) RETURNING "ID"`)
+	row := tx.QueryRow(createTemplateQuery, userId, template.Title, template.Description)
+	if err = row.Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err = insertTemplateItems(tx, id, template.Items); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *TemplatePostgres) GetAll(userId int) ([]model.ListTemplate, error) {
+	var templates []model.ListTemplate
+	query := fmt.Sprintf(`SELECT T."ID", T."Title", T."Description" FROM "List_Template" T WHERE T."User_id" = $1 ORDER BY T."Title"`)
+	err := r.db.Select(&templates, query, userId)
+
+	return templates, err
+}
+
+func (r *TemplatePostgres) GetById(userId, templateId int) (model.ListTemplate, error) {
+	var template model.ListTemplate
+	query := fmt.Sprintf(`SELECT T."ID", T."Title", T."Description" FROM "List_Template" T WHERE T."User_id" = $1 AND T."ID" = $2`)
+	if err := r.db.Get(&template, query, userId, templateId); err != nil {
+		return template, err
+	}
+
+	itemsQuery := fmt.Sprintf(`SELECT TI."ID", TI."Title", TI."Description", TI."Quantity", TI."Unit", TI."Category" FROM "Template_Item" TI
+									WHERE TI."Template_id" = $1 ORDER BY TI."Position", TI."ID"`)
+	err := r.db.Select(&template.Items, itemsQuery, templateId)
+
+	return template, err
+}
+
+func (r *TemplatePostgres) Update(userId, templateId int, input model.UpdateTemplateInput) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var id int
+	lockQuery := fmt.Sprintf(`SELECT T."ID" FROM "List_Template" T WHERE T."User_id" = $1 AND T."ID" = $2 FOR UPDATE`)
+	if err = tx.QueryRow(lockQuery, userId, templateId).Scan(&id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Title != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Title"=$%d`, argId))
+		args = append(args, *input.Title)
+		argId++
+	}
+	if input.Description != nil {
+		setValues = append(setValues, fmt.Sprintf(`"Description"=$%d`, argId))
+		args = append(args, *input.Description)
+		argId++
+	}
+
+	if len(setValues) > 0 {
+		setQuery := strings.Join(setValues, ", ")
+		query := fmt.Sprintf(`UPDATE "List_Template" T SET %s WHERE T."ID" = $%d`, setQuery, argId)
+		args = append(args, templateId)
+
+		if _, err = tx.Exec(query, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if input.Items != nil {
+		deleteItemsQuery := fmt.Sprintf(`DELETE FROM "Template_Item" TI WHERE TI."Template_id" = $1`)
+		if _, err = tx.Exec(deleteItemsQuery, templateId); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err = insertTemplateItems(tx, templateId, *input.Items); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *TemplatePostgres) Delete(userId, templateId int) error {
+	query := fmt.Sprintf(`DELETE FROM "List_Template" T WHERE T."User_id" = $1 AND T."ID" = $2`)
+	_, err := r.db.Exec(query, userId, templateId)
+
+	return err
+}
+
+// Instantiate creates a new list with all template items in one transaction.
+func (r *TemplatePostgres) Instantiate(userId, templateId int, title string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	listId, err := instantiateTemplate(tx, userId, templateId, title)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return listId, tx.Commit()
+}
+
+// AddToList adds all template items to an existing list of the user.
+func (r *TemplatePostgres) AddToList(userId, templateId, listId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = addTemplateItems(tx, userId, templateId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// instantiateTemplate creates a list of the user with the items of the template and returns its ID.
+// An empty title is replaced with the title of the template.
+func instantiateTemplate(tx *sql.Tx, userId, templateId int, title string) (int, error) {
+	var listId int
+	createListQuery := fmt.Sprintf(`INSERT INTO "Shopping_List" ("Title", "User_id", "Description", "Position")
+									SELECT COALESCE(NULLIF($3, ''), T."Title"), T."User_id", T."Description",
+										(SELECT COALESCE(MAX(L."Position"), 0) + 1 FROM "Shopping_List" L WHERE L."User_id" = $1)
+									FROM "List_Template" T WHERE T."User_id" = $1 AND T."ID" = $2
+									RETURNING "ID"`)
+	row := tx.QueryRow(createListQuery, userId, templateId, title)
+	if err := row.Scan(&listId); err != nil {
+		return 0, err
+	}
+
+	return listId, addTemplateItems(tx, userId, templateId, listId)
+}
+
+// addTemplateItems adds the items of the template to the list, both must belong to the user.
+func addTemplateItems(tx *sql.Tx, userId, templateId, listId int) error {
+	query := fmt.Sprintf(`INSERT INTO "Shopping_Item" ("List_id", "Title", "Description", "Quantity", "Unit", "Category")
+									SELECT L."ID", TI."Title", TI."Description", TI."Quantity", TI."Unit", TI."Category" FROM "Template_Item" TI
+									INNER JOIN "List_Template" T on TI."Template_id" = T."ID"
+									INNER JOIN "Shopping_List" L on L."User_id" = T."User_id"
+									WHERE T."User_id" = $1 AND T."ID" = $2 AND L."ID" = $3 ORDER BY TI."Position", TI."ID"`)
+	_, err := tx.Exec(query, userId, templateId, listId)
+
+	return err
+}
+
+func insertTemplateItems(tx *sql.Tx, templateId int, items []model.TemplateItem) error {
+	query := fmt.Sprintf(`INSERT INTO "Template_Item" ("Template_id", "Title", "Description", "Quantity", "Unit", "Category", "Position")
+									VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	for i, item := range items {
+		if _, err := tx.Exec(query, templateId, item.Title, item.Description, item.Quantity, item.Unit, item.Category, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+const tripColumns = `T."ID", T."User_id", T."List_id", T."Store_id", T."Shopper", T."Currency", T."Started_at", T."Finished_at",
+										(SELECT COALESCE(sum(P."Price"), 0) FROM "Purchase" P WHERE P."Trip_id" = T."ID") AS "Total"`
+
+type TripPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTripPostgres(db *sqlx.DB) *TripPostgres {
+	return &TripPostgres{db: db}
+}
+
+// Start starts a trip with the list in the store, the store of the list if storeId is nil.
+func (r *TripPostgres) Start(userId, listId int, storeId *int, shopper string) (int, error) {
+	var id int
+	query := fmt.Sprintf(`INSERT INTO "Trip" ("User_id", "List_id", "Store_id", "Shopper", "Currency")
+									SELECT L."User_id", L."ID", COALESCE($3, L."Store_id"), COALESCE(NULLIF($4, ''), U."Username"), L."Currency"
+									FROM "Shopping_List" L INNER JOIN "User" U on L."User_id" = U."ID"
+									WHERE L."User_id" = $1 AND L."ID" = $2 AND NOT EXISTS(
+										SELECT FROM "Trip" T WHERE T."List_id" = L."ID" AND T."Finished_at" IS NULL)
+									RETURNING "ID"`)
+	err := r.db.QueryRow(query, userId, listId, storeId, shopper).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.New("list already has an active trip")
+	}
+
+	return id, err
+}
+
+// GetAll returns the trips of the user without purchases, the latest first.
+func (r *TripPostgres) GetAll(userId int) ([]model.Trip, error) {
+	var trips []model.Trip
+	query := fmt.Sprintf(`SELECT %s FROM "Trip" T WHERE T."User_id" = $1 ORDER BY T."Started_at" DESC, T."ID" DESC`, tripColumns)
+	err := r.db.Select(&trips, query, userId)
+
+	return trips, err
+}
+
+// GetById returns the trip with its purchases in the order they were checked off.
+func (r *TripPostgres) GetById(userId, tripId int) (model.Trip, error) {
+	var trip model.Trip
+	query := fmt.Sprintf(`SELECT %s FROM "Trip" T WHERE T."User_id" = $1 AND T."ID" = $2`, tripColumns)
+	if err := r.db.Get(&trip, query, userId, tripId); err != nil {
+		return trip, err
+	}
+
+	purchasesQuery := fmt.Sprintf(`SELECT P."ID", P."Item_id", P."Title", P."Quantity", P."Unit", P."Category", P."Price", P."Checked_at"
+									FROM "Purchase" P WHERE P."Trip_id" = $1 ORDER BY P."Checked_at", P."ID"`)
+	if err := r.db.Select(&trip.Purchases, purchasesQuery, tripId); err != nil {
+		return trip, err
+	}
+
+	return trip, nil
+}
+
+// Finish finishes the active trip and unchecks or removes the items of the list purchased during it.
+// Unchecked items get the clocks of the write. The list version is incremented if any item changed.
+func (r *TripPostgres) Finish(userId, tripId int, purchased string, clocks model.FieldClocks) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var listId sql.NullInt64
+	finishQuery := fmt.Sprintf(`UPDATE "Trip" T SET "Finished_at" = now()
+									WHERE T."User_id" = $1 AND T."ID" = $2 AND T."Finished_at" IS NULL
+									RETURNING T."List_id"`)
+	if err = tx.QueryRow(finishQuery, userId, tripId).Scan(&listId); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("unknown or finished trip")
+		}
+		return err
+	}
+
+	var query string
+	switch purchased {
+	case model.PurchasedUncheck:
+		query = fmt.Sprintf(`UPDATE "Shopping_Item" I SET "Checked" = false, "Clocks" = I."Clocks" || $3::jsonb,
+										"Version" = I."Version" + 1, "Updated_at" = now()
+									FROM "Purchase" P WHERE P."Trip_id" = $1 AND P."Item_id" = I."ID" AND I."List_id" = $2 AND I."Checked"`)
+	case model.PurchasedRemove:
+		query = fmt.Sprintf(`DELETE FROM "Shopping_Item" I USING "Purchase" P
+									WHERE P."Trip_id" = $1 AND P."Item_id" = I."ID" AND I."List_id" = $2 AND I."Checked"`)
+	}
+	if query == "" || !listId.Valid {
+		return tx.Commit()
+	}
+
+	args := []interface{}{tripId, listId.Int64}
+	if purchased == model.PurchasedUncheck {
+		args = append(args, clocks)
+	}
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected > 0 {
+		query := fmt.Sprintf(`UPDATE "Shopping_List" L SET "Version" = L."Version" + 1, "Updated_at" = now() WHERE L."ID" = $1`)
+		if _, err = tx.Exec(query, listId.Int64); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// autocompleteCandidates is the number of matching titles ranked for a query
+	autocompleteCandidates = 50
+	// autocompleteHalfLife is the time after which the recency of a title weighs half as much
+	autocompleteHalfLife = 30 * 24 * time.Hour
+)
+
+type AutocompleteService struct {
+	repo repository.ItemHistory
+}
+
+func NewAutocompleteService(repo repository.ItemHistory) *AutocompleteService {
+	return &AutocompleteService{repo: repo}
+}
+
+// Complete returns up to limit titles the user has used before that match the query,
+// ranked by how well they match, how often and how recently they were used.
+func (s *AutocompleteService) Complete(userId int, query string, limit int) ([]model.Completion, error) {
+	key := autocompleteKey(query)
+	if key == "" {
+		return nil, errors.New("query is empty")
+	}
+
+	completions, err := s.repo.Search(userId, key, max(limit, autocompleteCandidates))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range completions {
+		completions[i].Score = math.Round(rankCompletion(completions[i], now)*1000) / 1000
+	}
+	sort.SliceStable(completions, func(i, j int) bool {
+		return completions[i].Score > completions[j].Score
+	})
+
+	return completions[:min(limit, len(completions))], nil
+}
+
+// autocompleteKey normalizes the title like categoryKey and replaces ё with е,
+// as the titles of the item history are stored.
+func autocompleteKey(title string) string {
+	return strings.ReplaceAll(categoryKey(title), "ё", "е")
+}
+
+// rankCompletion multiplies how well the title matches, a prefix match being the best,
+// by the logarithm of its uses and its recency, which halves every half-life but never
+// falls below a half so frequent titles are still suggested after a break.
+func rankCompletion(completion model.Completion, now time.Time) float64 {
+	match := completion.Similarity * 0.8
+	if completion.Prefix {
+		match = 1
+	}
+
+	frequency := 1 + math.Log(float64(max(completion.Uses, 1)))
+	age := math.Max(0, now.Sub(completion.LastUsed).Hours())
+	recency := 0.5 + 0.5*math.Pow(0.5, age/autocompleteHalfLife.Hours())
+
+	return match * frequency * recency
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"sort"
+	"strings"
+)
+
+// seedCategories is the built-in dictionary of common products. Keys are English words
+// and Russian stems: a word matches a key of at least four letters it starts with when at
+// most three letters of an ending are left, so "apples" matches "apple" and "молока" matches
+// "молок". Shorter keys match only with an inflection ending, so "eggs" matches "egg" and
+// "лука" matches "лук", but "team" does not match "tea".
+var seedCategories = map[string]string{
+	"apple": "produce", "banana": "produce", "orange": "produce", "lemon": "produce", "lime": "produce",
+	"tomato": "produce", "potato": "produce", "onion": "produce", "garlic": "produce", "carrot": "produce",
+	"cucumber": "produce", "lettuce": "produce", "salad": "produce", "cabbage": "produce", "avocado": "produce",
+	"grape": "produce", "pear": "produce", "berry": "produce", "berries": "produce", "fruit": "produce",
+	"vegetable": "produce", "herb": "produce", "dill": "produce", "parsley": "produce", "mushroom": "produce",
+
+	"milk": "dairy", "cheese": "dairy", "yogurt": "dairy", "yoghurt": "dairy", "butter": "dairy",
+	"cream": "dairy", "kefir": "dairy", "egg": "dairy",
+
+	"bread": "bakery", "baguette": "bakery", "bun": "bakery", "croissant": "bakery", "bagel": "bakery",
+	"cake": "bakery", "pie": "bakery",
+
+	"chicken": "meat", "beef": "meat", "pork": "meat", "ham": "meat", "bacon": "meat", "sausage": "meat",
+	"turkey": "meat", "mince": "meat", "steak": "meat", "lamb": "meat",
+
+	"fish": "fish", "salmon": "fish", "tuna": "fish", "shrimp": "fish", "prawn": "fish", "cod": "fish",
+
+	"frozen": "frozen", "ice cream": "frozen", "dumpling": "frozen",
+
+	"rice": "pantry", "pasta": "pantry", "spaghetti": "pantry", "flour": "pantry", "sugar": "pantry",
+	"salt": "pantry", "oil": "pantry", "vinegar": "pantry", "cereal": "pantry", "oats": "pantry",
+	"beans": "pantry", "lentil": "pantry", "honey": "pantry", "jam": "pantry", "sauce": "pantry",
+	"ketchup": "pantry", "mayonnaise": "pantry", "spice": "pantry",
+
+	"chips": "snacks", "crisps": "snacks", "chocolate": "snacks", "cookie": "snacks", "biscuit": "snacks",
+	"candy": "snacks", "nut": "snacks", "popcorn": "snacks", "cracker": "snacks",
+
+	"water": "beverages", "juice": "beverages", "soda": "beverages", "cola": "beverages", "beer": "beverages",
+	"wine": "beverages", "coffee": "beverages", "tea": "beverages", "lemonade": "beverages",
+
+	"diaper": "baby", "nappy": "baby", "nappies": "baby",
+
+	"cat food": "pets", "dog food": "pets", "litter": "pets",
+
+	"detergent": "household", "toilet paper": "household", "paper towel": "household", "sponge": "household",
+	"bleach": "household", "trash bag": "household", "battery": "household", "batteries": "household",
+	"foil": "household", "napkin": "household",
+
+	"shampoo": "personal care", "toothpaste": "personal care", "toothbrush": "personal care",
+	"deodorant": "personal care", "soap": "personal care", "razor": "personal care", "conditioner": "personal care",
+
+	"яблок": "produce", "банан": "produce", "апельсин": "produce", "лимон": "produce", "помидор": "produce",
+	"томат": "produce", "картош": "produce", "картофел": "produce", "лук": "produce", "чеснок": "produce",
+	"морков": "produce", "огурц": "produce", "огурец": "produce", "капуст": "produce", "салат": "produce",
+	"фрукт": "produce", "овощ": "produce", "зелен": "produce", "укроп": "produce", "петрушк": "produce",
+	"груш": "produce", "виноград": "produce", "гриб": "produce",
+
+	"молок": "dairy", "сыр": "dairy", "йогурт": "dairy", "кефир": "dairy", "сметан": "dairy",
+	"творог": "dairy", "сливочн": "dairy", "яйц": "dairy", "яиц": "dairy", "ряженк": "dairy",
+
+	"хлеб": "bakery", "батон": "bakery", "булк": "bakery", "булочк": "bakery", "багет": "bakery",
+	"торт": "bakery", "пирог": "bakery", "лаваш": "bakery",
+
+	"мяс": "meat", "куриц": "meat", "курин": "meat", "говядин": "meat", "свинин": "meat",
+	"колбас": "meat", "сосиск": "meat", "фарш": "meat", "ветчин": "meat", "бекон": "meat", "индейк": "meat",
+
+	"рыб": "fish", "лосос": "fish", "семг": "fish", "тунец": "fish", "тунц": "fish", "креветк": "fish",
+
+	"морожен": "frozen", "пельмен": "frozen", "заморожен": "frozen",
+
+	"рис": "pantry", "макарон": "pantry", "мук": "pantry", "сахар": "pantry", "соль": "pantry",
+	"круп": "pantry", "гречк": "pantry", "овсянк": "pantry", "подсолнечн": "pantry", "уксус": "pantry",
+	"мед": "pantry", "варень": "pantry", "соус": "pantry", "кетчуп": "pantry", "майонез": "pantry",
+	"специ": "pantry",
+
+	"чипс": "snacks", "шоколад": "snacks", "печень": "snacks", "конфет": "snacks", "орех": "snacks",
+	"сухар": "snacks", "попкорн": "snacks",
+
+	"вода": "beverages", "воды": "beverages", "сок": "beverages", "газировк": "beverages", "пиво": "beverages",
+	"вино": "beverages", "кофе": "beverages", "чай": "beverages", "лимонад": "beverages",
+
+	"подгуз": "baby", "пюре": "baby",
+
+	"корм": "pets", "наполнител": "pets",
+
+	"порош": "household", "салфет": "household", "туалетн": "household", "губк": "household",
+	"батарейк": "household", "фольг": "household", "пакеты для мусора": "household",
+
+	"шампун": "personal care", "зубн": "personal care", "мыло": "personal care", "дезодорант": "personal care",
+	"бритв": "personal care", "бальзам": "personal care",
+}
+
+// minSeedStem is the shortest key a word matches with any ending, shorter keys like
+// "tea" or "лук" would match "team" and "лукум".
+const minSeedStem = 4
+
+// inflectionEndings are the plural and case endings of English and Russian nouns.
+var inflectionEndings = []string{
+	"s", "es",
+	"а", "я", "ы", "и", "у", "ю", "е", "о", "ом", "ем", "ой", "ей", "ов", "ев", "ам", "ям", "ами", "ями", "ах", "ях",
+}
+
+// categoryKey normalizes the title of an item for the category dictionaries.
+func categoryKey(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// seedCategory classifies the title by the built-in dictionary: the whole title first,
+// then its words from the first one. It returns an empty category for unknown products.
+func seedCategory(title string) string {
+	key := categoryKey(title)
+	if category, ok := seedCategories[key]; ok {
+		return category
+	}
+
+	for _, word := range strings.Fields(key) {
+		if category, ok := seedCategories[word]; ok {
+			return category
+		}
+	}
+
+	for _, word := range strings.Fields(key) {
+		runes := []rune(word)
+		for end := len(runes) - 1; end >= minSeedStem && end >= len(runes)-3; end-- {
+			if category, ok := seedCategories[string(runes[:end])]; ok {
+				return category
+			}
+		}
+		for _, ending := range inflectionEndings {
+			stem, ok := strings.CutSuffix(word, ending)
+			if ok && len([]rune(stem)) >= 3 {
+				if category, ok := seedCategories[stem]; ok {
+					return category
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// groupByCategory groups the items by category: built-in categories in their order,
+// then user-defined ones alphabetically and items without a category last as other.
+func groupByCategory(items []model.ShoppingItem) []model.ItemCategoryGroup {
+	groups := make([]model.ItemCategoryGroup, 0)
+	index := make(map[string]int)
+
+	for _, item := range items {
+		category := item.Category
+		if category == "" {
+			category = model.CategoryOther
+		}
+
+		i, ok := index[category]
+		if !ok {
+			i = len(groups)
+			index[category] = i
+			groups = append(groups, model.ItemCategoryGroup{Category: category})
+		}
+		groups[i].Items = append(groups[i].Items, item)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		ri, rj := model.CategoryRank(groups[i].Category), model.CategoryRank(groups[j].Category)
+		if ri != rj {
+			return ri < rj
+		}
+		return groups[i].Category < groups[j].Category
+	})
+
+	return groups
+}
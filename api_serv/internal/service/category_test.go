@@ -0,0 +1,36 @@
+package service
+
+import "testing"
+
+func TestSeedCategory(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Apples", "produce"},
+		{"green apples", "produce"},
+		{"eggs", "dairy"},
+		{"buns", "bakery"},
+		{"ice cream", "frozen"},
+		{"молока", "dairy"},
+		{"лука", "produce"},
+		{"луком", "produce"},
+		{"картошка", "produce"},
+		{"рыбы", "fish"},
+
+		{"team", ""},
+		{"tear", ""},
+		{"code", ""},
+		{"piece", ""},
+		{"pier", ""},
+		{"bunch", ""},
+		{"лукум", ""},
+		{"unknown thing", ""},
+	}
+
+	for _, tt := range tests {
+		if got := seedCategory(tt.title); got != tt.want {
+			t.Errorf("seedCategory(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import "github.com/elecshen/shopping_list/internal/model"
+
+// mergeItemFields treats every item field as a last-writer-wins register. It keeps only the
+// fields of input written at ts after the stored register and returns them with their clocks.
+// Because timestamps are totally ordered, applying the same writes in any order gives the same item.
+func mergeItemFields(clocks model.FieldClocks, input model.UpdateItemInput, ts model.Timestamp) model.UpdateItemInput {
+	newer := func(field string) bool {
+		return ts.Compare(clocks[field]) > 0
+	}
+
+	merged := model.UpdateItemInput{Clocks: model.FieldClocks{}}
+	if input.Title != nil && newer(model.FieldTitle) {
+		merged.Title = input.Title
+		merged.Clocks[model.FieldTitle] = ts
+	}
+	if input.Description != nil && newer(model.FieldDescription) {
+		merged.Description = input.Description
+		merged.Clocks[model.FieldDescription] = ts
+	}
+	if input.Checked != nil && newer(model.FieldChecked) {
+		merged.Checked = input.Checked
+		merged.Clocks[model.FieldChecked] = ts
+	}
+	if input.Quantity != nil && newer(model.FieldQuantity) {
+		merged.Quantity = input.Quantity
+		merged.Clocks[model.FieldQuantity] = ts
+	}
+	if input.Unit != nil && newer(model.FieldUnit) {
+		merged.Unit = input.Unit
+		merged.Clocks[model.FieldUnit] = ts
+	}
+	if input.Category != nil && newer(model.FieldCategory) {
+		merged.Category = input.Category
+		merged.Clocks[model.FieldCategory] = ts
+	}
+	if input.EstimatedPrice != nil && newer(model.FieldEstimatedPrice) {
+		merged.EstimatedPrice = input.EstimatedPrice
+		merged.Clocks[model.FieldEstimatedPrice] = ts
+	}
+	if input.ActualPrice != nil && newer(model.FieldActualPrice) {
+		merged.ActualPrice = input.ActualPrice
+		merged.Clocks[model.FieldActualPrice] = ts
+	}
+
+	return merged
+}
+
+// stampItemFields returns clocks for a write of every field set in input at ts.
+func stampItemFields(input model.UpdateItemInput, ts model.Timestamp) model.FieldClocks {
+	return mergeItemFields(model.FieldClocks{}, input, ts).Clocks
+}
+
+// orSet is the observed-remove set of add tags that keeps an item in its list.
+// The item is present while at least one add tag has not been removed, so a remove
+// only cancels the adds its author has seen and a concurrent add wins.
+type orSet struct {
+	adds    map[string]bool
+	removes map[string]bool
+}
+
+func newORSet(tags []model.MembershipTag) orSet {
+	set := orSet{adds: make(map[string]bool), removes: make(map[string]bool)}
+	for _, t := range tags {
+		set.adds[t.Tag] = true
+		if t.Removed {
+			set.removes[t.Tag] = true
+		}
+	}
+
+	return set
+}
+
+func (s orSet) remove(tags []string) {
+	for _, tag := range tags {
+		s.removes[tag] = true
+	}
+}
+
+// live returns the add tags that have not been removed.
+func (s orSet) live() []string {
+	tags := make([]string, 0)
+	for tag := range s.adds {
+		if !s.removes[tag] {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func (s orSet) contains() bool {
+	return len(s.live()) > 0
+}
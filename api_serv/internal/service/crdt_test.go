@@ -0,0 +1,385 @@
+package service
+
+import (
+	"fmt"
+	"github.com/elecshen/shopping_list/internal/model"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const (
+	crdtRuns   = 500
+	crdtWrites = 6
+)
+
+var crdtNodes = []string{"a", "b", "c"}
+
+// write is an update of some item fields by a replica at a timestamp.
+type write struct {
+	input model.UpdateItemInput
+	ts    model.Timestamp
+}
+
+// itemRegisters is the stored state of an item: the last value of every field and its clock.
+type itemRegisters struct {
+	values model.UpdateItemInput
+	clocks model.FieldClocks
+}
+
+// apply stores a write the way mergeFields does: only the fields newer than the stored ones
+// are written and their clocks are merged into the stored clocks.
+func (r itemRegisters) apply(w write) itemRegisters {
+	merged := mergeItemFields(r.clocks, w.input, w.ts)
+
+	next := itemRegisters{values: r.values, clocks: model.FieldClocks{}}
+	for field, ts := range r.clocks {
+		next.clocks[field] = ts
+	}
+	for field, ts := range merged.Clocks {
+		next.clocks[field] = ts
+	}
+	overlay(&next.values, merged)
+
+	return next
+}
+
+// join merges two states by applying every field of other as a write at its clock.
+func (r itemRegisters) join(other itemRegisters) itemRegisters {
+	for _, w := range other.writes() {
+		r = r.apply(w)
+	}
+
+	return r
+}
+
+// writes splits the state into single field writes.
+func (r itemRegisters) writes() []write {
+	values := []struct {
+		field string
+		input model.UpdateItemInput
+	}{
+		{model.FieldTitle, model.UpdateItemInput{Title: r.values.Title}},
+		{model.FieldDescription, model.UpdateItemInput{Description: r.values.Description}},
+		{model.FieldChecked, model.UpdateItemInput{Checked: r.values.Checked}},
+		{model.FieldQuantity, model.UpdateItemInput{Quantity: r.values.Quantity}},
+		{model.FieldUnit, model.UpdateItemInput{Unit: r.values.Unit}},
+		{model.FieldCategory, model.UpdateItemInput{Category: r.values.Category}},
+		{model.FieldEstimatedPrice, model.UpdateItemInput{EstimatedPrice: r.values.EstimatedPrice}},
+		{model.FieldActualPrice, model.UpdateItemInput{ActualPrice: r.values.ActualPrice}},
+	}
+
+	writes := make([]write, 0, len(values))
+	for _, v := range values {
+		if ts, ok := r.clocks[v.field]; ok {
+			writes = append(writes, write{input: v.input, ts: ts})
+		}
+	}
+
+	return writes
+}
+
+func overlay(values *model.UpdateItemInput, input model.UpdateItemInput) {
+	if input.Title != nil {
+		values.Title = input.Title
+	}
+	if input.Description != nil {
+		values.Description = input.Description
+	}
+	if input.Checked != nil {
+		values.Checked = input.Checked
+	}
+	if input.Quantity != nil {
+		values.Quantity = input.Quantity
+	}
+	if input.Unit != nil {
+		values.Unit = input.Unit
+	}
+	if input.Category != nil {
+		values.Category = input.Category
+	}
+	if input.EstimatedPrice != nil {
+		values.EstimatedPrice = input.EstimatedPrice
+	}
+	if input.ActualPrice != nil {
+		values.ActualPrice = input.ActualPrice
+	}
+}
+
+func emptyRegisters() itemRegisters {
+	return itemRegisters{clocks: model.FieldClocks{}}
+}
+
+func applyAll(writes []write) itemRegisters {
+	state := emptyRegisters()
+	for _, w := range writes {
+		state = state.apply(w)
+	}
+
+	return state
+}
+
+// randomWrites returns writes with distinct timestamps. Wall times are drawn from a narrow range
+// so that ties broken by the logical counter and the node are common.
+func randomWrites(rnd *rand.Rand, n int) []write {
+	used := make(map[model.Timestamp]bool)
+	writes := make([]write, 0, n)
+	for len(writes) < n {
+		ts := model.Timestamp{Wall: rnd.Int63n(3), Logical: rnd.Intn(2), Node: crdtNodes[rnd.Intn(len(crdtNodes))]}
+		if used[ts] {
+			continue
+		}
+		used[ts] = true
+		writes = append(writes, write{input: randomInput(rnd, ts), ts: ts})
+	}
+
+	return writes
+}
+
+// randomInput sets a random non-empty subset of the fields to values unique to the write.
+func randomInput(rnd *rand.Rand, ts model.Timestamp) model.UpdateItemInput {
+	var input model.UpdateItemInput
+	for input.Validate() != nil {
+		tag := ts.String()
+		if rnd.Intn(2) == 0 {
+			input.Title = &tag
+		}
+		if rnd.Intn(2) == 0 {
+			description := "description " + tag
+			input.Description = &description
+		}
+		if rnd.Intn(2) == 0 {
+			checked := rnd.Intn(2) == 0
+			input.Checked = &checked
+		}
+		if rnd.Intn(2) == 0 {
+			quantity := float64(ts.Wall*10+int64(ts.Logical)) + 1
+			input.Quantity = &quantity
+		}
+		if rnd.Intn(2) == 0 {
+			category := "category " + tag
+			input.Category = &category
+		}
+		if rnd.Intn(2) == 0 {
+			price := ts.Wall*100 + int64(rnd.Intn(100))
+			input.ActualPrice = &price
+		}
+	}
+
+	return input
+}
+
+func shuffled(rnd *rand.Rand, writes []write) []write {
+	result := append([]write(nil), writes...)
+	rnd.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+
+	return result
+}
+
+func assertSameRegisters(t *testing.T, want, got itemRegisters, msg string) {
+	t.Helper()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("%s:\nwant %s\n got %s", msg, describeRegisters(want), describeRegisters(got))
+	}
+}
+
+func describeRegisters(r itemRegisters) string {
+	s := ""
+	for _, w := range r.writes() {
+		s += fmt.Sprintf("%+v@%s ", w.input, w.ts)
+	}
+
+	return s
+}
+
+func TestMergeItemFieldsOrderIndependent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for run := 0; run < crdtRuns; run++ {
+		writes := randomWrites(rnd, crdtWrites)
+		want := applyAll(writes)
+		assertSameRegisters(t, want, applyAll(shuffled(rnd, writes)), "writes applied in another order")
+	}
+}
+
+func TestMergeItemFieldsLastWriterWins(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for run := 0; run < crdtRuns; run++ {
+		writes := randomWrites(rnd, crdtWrites)
+		state := applyAll(shuffled(rnd, writes))
+
+		sort.Slice(writes, func(i, j int) bool {
+			return writes[i].ts.Compare(writes[j].ts) < 0
+		})
+		var want model.UpdateItemInput
+		for _, w := range writes {
+			overlay(&want, w.input)
+		}
+		if !reflect.DeepEqual(want, state.values) {
+			t.Fatalf("fields are not the ones of the latest writes: want %+v, got %+v", want, state.values)
+		}
+	}
+}
+
+func TestMergeItemFieldsCommutative(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for run := 0; run < crdtRuns; run++ {
+		writes := randomWrites(rnd, crdtWrites)
+		split := rnd.Intn(len(writes) + 1)
+		a, b := applyAll(writes[:split]), applyAll(writes[split:])
+
+		assertSameRegisters(t, a.join(b), b.join(a), "join(a, b) != join(b, a)")
+	}
+}
+
+func TestMergeItemFieldsAssociative(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	for run := 0; run < crdtRuns; run++ {
+		writes := randomWrites(rnd, crdtWrites)
+		i := rnd.Intn(len(writes) + 1)
+		j := i + rnd.Intn(len(writes)-i+1)
+		a, b, c := applyAll(writes[:i]), applyAll(writes[i:j]), applyAll(writes[j:])
+
+		assertSameRegisters(t, a.join(b).join(c), a.join(b.join(c)), "join(join(a, b), c) != join(a, join(b, c))")
+	}
+}
+
+func TestMergeItemFieldsIdempotent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	for run := 0; run < crdtRuns; run++ {
+		writes := randomWrites(rnd, crdtWrites)
+		state := applyAll(writes)
+
+		assertSameRegisters(t, state, state.join(state), "join(a, a) != a")
+		for _, w := range writes {
+			assertSameRegisters(t, state, state.apply(w), "a write delivered twice changed the item")
+		}
+	}
+}
+
+// tagOp is an add of a tag or a remove of the tags its author has observed.
+type tagOp struct {
+	add    string
+	remove []string
+}
+
+// storeTags applies the operations the way ItemStatePostgres does: an add never revives
+// a removed tag and a remove marks tags removed even if their add has not arrived yet.
+func storeTags(ops []tagOp) []model.MembershipTag {
+	removed := make(map[string]bool)
+	for _, op := range ops {
+		if op.add != "" {
+			if _, ok := removed[op.add]; !ok {
+				removed[op.add] = false
+			}
+		}
+		for _, tag := range op.remove {
+			removed[tag] = true
+		}
+	}
+
+	tags := make([]model.MembershipTag, 0, len(removed))
+	for tag, r := range removed {
+		tags = append(tags, model.MembershipTag{Tag: tag, Removed: r})
+	}
+
+	return tags
+}
+
+func liveTags(ops []tagOp) []string {
+	live := newORSet(storeTags(ops)).live()
+	sort.Strings(live)
+
+	return live
+}
+
+// randomTagOps returns adds with unique tags and removes observing a random part of the adds before them.
+func randomTagOps(rnd *rand.Rand, n int) []tagOp {
+	ops := make([]tagOp, 0, n)
+	added := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if len(added) == 0 || rnd.Intn(2) == 0 {
+			tag := fmt.Sprintf("tag%d", i)
+			added = append(added, tag)
+			ops = append(ops, tagOp{add: tag})
+			continue
+		}
+
+		observed := make([]string, 0)
+		for _, tag := range added {
+			if rnd.Intn(2) == 0 {
+				observed = append(observed, tag)
+			}
+		}
+		ops = append(ops, tagOp{remove: observed})
+	}
+
+	return ops
+}
+
+func TestORSetOrderIndependent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(6))
+	for run := 0; run < crdtRuns; run++ {
+		ops := randomTagOps(rnd, 8)
+		want := liveTags(ops)
+
+		shuffledOps := append([]tagOp(nil), ops...)
+		rnd.Shuffle(len(shuffledOps), func(i, j int) {
+			shuffledOps[i], shuffledOps[j] = shuffledOps[j], shuffledOps[i]
+		})
+		if got := liveTags(shuffledOps); !reflect.DeepEqual(want, got) {
+			t.Fatalf("operations applied in another order: want %v, got %v", want, got)
+		}
+		if got := liveTags(append(ops, ops...)); !reflect.DeepEqual(want, got) {
+			t.Fatalf("operations delivered twice: want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestORSetAddWins(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for run := 0; run < crdtRuns; run++ {
+		ops := randomTagOps(rnd, 8)
+
+		observed := make(map[string]bool)
+		for _, op := range ops {
+			for _, tag := range op.remove {
+				observed[tag] = true
+			}
+		}
+		want := make([]string, 0)
+		for _, op := range ops {
+			if op.add != "" && !observed[op.add] {
+				want = append(want, op.add)
+			}
+		}
+		sort.Strings(want)
+
+		if got := liveTags(ops); !reflect.DeepEqual(want, got) {
+			t.Fatalf("live tags must be the adds no remove has observed: want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestORSetConcurrentAddSurvivesRemove(t *testing.T) {
+	set := newORSet(storeTags([]tagOp{{add: "phone"}}))
+	observed := set.live()
+
+	// the laptop adds the item again while the phone removes the add it has seen
+	set = newORSet(storeTags([]tagOp{{add: "phone"}, {add: "laptop"}}))
+	set.remove(observed)
+
+	if !set.contains() {
+		t.Fatal("a concurrent add must keep the item")
+	}
+	if live := set.live(); !reflect.DeepEqual(live, []string{"laptop"}) {
+		t.Fatalf("want the laptop add live, got %v", live)
+	}
+
+	set.remove(set.live())
+	if set.contains() {
+		t.Fatal("removing every observed add must remove the item")
+	}
+}
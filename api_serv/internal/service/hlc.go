@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"sync"
+	"time"
+)
+
+// MaxClockDrift limits how far ahead of the server a client clock may run.
+const MaxClockDrift = time.Minute
+
+// HybridClock issues hybrid logical clock timestamps: they follow physical time
+// but never go backwards and always order after every timestamp the clock has seen.
+type HybridClock struct {
+	mu   sync.Mutex
+	node string
+	now  func() time.Time
+	last model.Timestamp
+}
+
+func NewHybridClock(node string) *HybridClock {
+	return &HybridClock{node: node, now: time.Now}
+}
+
+// Now returns a timestamp for a local event.
+func (c *HybridClock) Now() model.Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wall := c.now().UnixMilli()
+	if wall > c.last.Wall {
+		c.last = model.Timestamp{Wall: wall, Node: c.node}
+	} else {
+		c.last = model.Timestamp{Wall: c.last.Wall, Logical: c.last.Logical + 1, Node: c.node}
+	}
+
+	return c.last
+}
+
+// Update advances the clock past a timestamp received from another node.
+func (c *HybridClock) Update(remote model.Timestamp) (model.Timestamp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wall := c.now().UnixMilli()
+	if remote.Wall-wall > MaxClockDrift.Milliseconds() {
+		return model.Timestamp{}, errors.New("clock is too far ahead of the server")
+	}
+
+	next := model.Timestamp{Wall: max(wall, c.last.Wall, remote.Wall), Node: c.node}
+	switch {
+	case next.Wall == c.last.Wall && next.Wall == remote.Wall:
+		next.Logical = max(c.last.Logical, remote.Logical) + 1
+	case next.Wall == c.last.Wall:
+		next.Logical = c.last.Logical + 1
+	case next.Wall == remote.Wall:
+		next.Logical = remote.Logical + 1
+	}
+	c.last = next
+
+	return next, nil
+}
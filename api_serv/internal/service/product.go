@@ -0,0 +1,160 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"math"
+	"sort"
+)
+
+// trendWindow is the number of previous observations the latest price is compared with,
+// trendThreshold is the relative change below which the price is stable.
+const (
+	trendWindow    = 5
+	trendThreshold = 0.05
+)
+
+type ProductService struct {
+	repo repository.Product
+}
+
+func NewProductService(repo repository.Product) *ProductService {
+	return &ProductService{repo: repo}
+}
+
+func (s *ProductService) GetAll(userId int) ([]model.Product, error) {
+	return s.repo.GetAll(userId)
+}
+
+// GetPrices returns the price history of the product with the comparison of stores and the price trend.
+func (s *ProductService) GetPrices(userId, productId int) (model.ProductPrices, error) {
+	prices := model.ProductPrices{Stores: make([]model.StorePrice, 0), Trend: model.TrendUnknown}
+
+	product, err := s.repo.GetById(userId, productId)
+	if err != nil {
+		return prices, err
+	}
+	prices.Product = product
+
+	prices.History, err = s.repo.GetPrices(productId)
+	if err != nil {
+		return prices, err
+	}
+	if len(prices.History) == 0 {
+		return prices, nil
+	}
+
+	latest := prices.History[0]
+	prices.Unit, _ = model.ReferenceUnit(latest.Unit)
+	prices.Currency = latest.Currency
+
+	comparable := make([]model.PriceObservation, 0, len(prices.History))
+	for i := range prices.History {
+		observation := &prices.History[i]
+		observation.UnitPrice = unitPrice(*observation)
+		if observation.Currency == prices.Currency && observation.UnitPrice > 0 &&
+			model.UnitsCompatible(observation.Unit, prices.Unit) {
+			comparable = append(comparable, *observation)
+		}
+	}
+
+	prices.Stores = compareStores(comparable)
+	if len(prices.Stores) > 0 {
+		prices.Cheapest = &prices.Stores[0]
+	}
+	prices.Trend, prices.Change = priceTrend(comparable)
+
+	return prices, nil
+}
+
+// unitPrice returns the price of the reference unit of the observation or 0 if it is unknown.
+func unitPrice(observation model.PriceObservation) float64 {
+	unit, ok := model.ReferenceUnit(observation.Unit)
+	if !ok {
+		return 0
+	}
+	quantity, err := model.ConvertQuantity(observation.Quantity, observation.Unit, unit)
+	if err != nil || quantity == 0 {
+		return 0
+	}
+
+	return math.Round(float64(observation.Price)/quantity*100) / 100
+}
+
+// compareStores returns the latest unit price of every store from the cheapest.
+// Observations go from the newest.
+func compareStores(observations []model.PriceObservation) []model.StorePrice {
+	stores := make([]model.StorePrice, 0)
+	seen := make(map[int]bool)
+	for _, observation := range observations {
+		if observation.StoreId == nil || seen[*observation.StoreId] {
+			continue
+		}
+		seen[*observation.StoreId] = true
+
+		store := model.StorePrice{StoreId: *observation.StoreId, UnitPrice: observation.UnitPrice, ObservedAt: observation.ObservedAt}
+		if observation.StoreTitle != nil {
+			store.StoreTitle = *observation.StoreTitle
+		}
+		stores = append(stores, store)
+	}
+
+	sort.SliceStable(stores, func(i, j int) bool {
+		return stores[i].UnitPrice < stores[j].UnitPrice
+	})
+
+	return stores
+}
+
+// priceTrend compares the latest unit price with the average of the previous ones.
+func priceTrend(observations []model.PriceObservation) (string, float64) {
+	if len(observations) < 2 {
+		return model.TrendUnknown, 0
+	}
+
+	previous := observations[1:min(len(observations), trendWindow+1)]
+	var sum float64
+	for _, observation := range previous {
+		sum += observation.UnitPrice
+	}
+	average := sum / float64(len(previous))
+	change := math.Round((observations[0].UnitPrice-average)/average*1000) / 1000
+
+	switch {
+	case change > trendThreshold:
+		return model.TrendUp, change
+	case change < -trendThreshold:
+		return model.TrendDown, change
+	default:
+		return model.TrendStable, change
+	}
+}
+
+// estimatePrice fills the estimated price of a new item from the latest price of the product,
+// preferably in the store of the list, scaled to the quantity of the item.
+func estimatePrice(repo repository.Product, userId int, list model.ShoppingList, item *model.ShoppingItem) error {
+	if item.EstimatedPrice != 0 {
+		return nil
+	}
+
+	observation, err := repo.GetLatestPrice(userId, categoryKey(item.Title), list.Currency, list.StoreId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if observation.Quantity == 0 || !model.UnitsCompatible(observation.Unit, item.Unit) {
+		return nil
+	}
+
+	quantity, err := model.ConvertQuantity(item.Quantity, item.Unit, observation.Unit)
+	if err != nil {
+		return err
+	}
+	item.EstimatedPrice = int64(math.Round(float64(observation.Price) * quantity / observation.Quantity))
+
+	return nil
+}
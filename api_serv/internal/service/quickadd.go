@@ -0,0 +1,212 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxQuickAddNumber bounds quantities so that a typo like "1e30 eggs" is not taken for a quantity.
+const maxQuickAddNumber = 1e9
+
+var numberWords = map[string]float64{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8,
+	"nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "dozen": 12, "half": 0.5, "couple": 2,
+
+	"один": 1, "одна": 1, "одно": 1, "два": 2, "две": 2, "три": 3, "четыре": 4, "пять": 5,
+	"шесть": 6, "семь": 7, "восемь": 8, "девять": 9, "десять": 10, "дюжина": 12,
+	"пол": 0.5, "половина": 0.5, "полтора": 1.5, "полторы": 1.5, "пара": 2,
+}
+
+// multiplySigns include the Cyrillic letter that is often typed instead of x.
+var multiplySigns = map[string]bool{"x": true, "×": true, "*": true, "х": true}
+
+var noteRegexp = regexp.MustCompile(`\(([^)]*)\)`)
+
+// parseQuickAdd splits free text into items. Items are separated by new lines, semicolons
+// or commas; every item may have a quantity with a unit before or after the title,
+// a multiplier like "3 x" and a note in parentheses. Entries without a title are
+// returned as unparsed.
+func parseQuickAdd(text string) ([]model.ShoppingItem, []string) {
+	items := make([]model.ShoppingItem, 0)
+	unparsed := make([]string, 0)
+
+	for _, entry := range splitQuickAdd(text) {
+		item, ok := parseQuickAddEntry(entry)
+		if !ok {
+			unparsed = append(unparsed, entry)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, unparsed
+}
+
+// splitQuickAdd splits the text by new lines, semicolons and commas. A comma between
+// digits is a decimal separator and does not split.
+func splitQuickAdd(text string) []string {
+	entries := make([]string, 0)
+	runes := []rune(text)
+	start := 0
+
+	for i, r := range runes {
+		separator := r == '\n' || r == ';' ||
+			r == ',' && !(i > 0 && unicode.IsDigit(runes[i-1]) && i+1 < len(runes) && unicode.IsDigit(runes[i+1]))
+		if !separator {
+			continue
+		}
+		if entry := strings.TrimSpace(string(runes[start:i])); entry != "" {
+			entries = append(entries, entry)
+		}
+		start = i + 1
+	}
+	if entry := strings.TrimSpace(string(runes[start:])); entry != "" {
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func parseQuickAddEntry(entry string) (model.ShoppingItem, bool) {
+	var item model.ShoppingItem
+
+	notes := make([]string, 0)
+	for _, match := range noteRegexp.FindAllStringSubmatch(entry, -1) {
+		if note := strings.TrimSpace(match[1]); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	item.Description = strings.Join(notes, "; ")
+
+	tokens := tokenizeQuickAdd(noteRegexp.ReplaceAllString(entry, " "))
+	title := make([]string, 0, len(tokens))
+	multiplier := 1.0
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		number, isNumber := parseQuickAddNumber(token)
+		// "two dozen"
+		for isNumber && i+1 < len(tokens) && isQuickAddNumber(tokens[i+1]) {
+			next, _ := parseQuickAddNumber(tokens[i+1])
+			number *= next
+			i++
+		}
+
+		switch {
+		case isNumber && i+1 < len(tokens) && multiplySigns[strings.ToLower(tokens[i+1])]:
+			// "3 x milk"
+			multiplier *= number
+			i++
+		case multiplySigns[strings.ToLower(token)] && i+1 < len(tokens) && isQuickAddNumber(tokens[i+1]):
+			// "milk x3"
+			number, _ = parseQuickAddNumber(tokens[i+1])
+			multiplier *= number
+			i++
+		case isNumber && item.Quantity == 0 && i+1 < len(tokens) && isQuickAddUnit(tokens[i+1]):
+			item.Quantity = number
+			item.Unit, _ = model.NormalizeUnit(strings.TrimSuffix(tokens[i+1], "."))
+			i++
+		case isNumber && item.Quantity == 0:
+			item.Quantity = number
+		case item.Quantity == 0 && len(title) == 0 && len([]rune(token)) > 2 && isQuickAddUnit(token):
+			// "pack of butter", a unit word alone means one unit
+			item.Quantity = 1
+			item.Unit, _ = model.NormalizeUnit(strings.TrimSuffix(token, "."))
+		default:
+			title = append(title, token)
+		}
+	}
+
+	if len(title) > 1 && strings.EqualFold(title[0], "of") {
+		// "2 packs of butter"
+		title = title[1:]
+	}
+	item.Title = strings.Join(title, " ")
+	if item.Title == "" {
+		return item, false
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+	item.Quantity = model.RoundQuantity(item.Quantity * multiplier)
+
+	return item, validQuickAddNumber(item.Quantity)
+}
+
+// tokenizeQuickAdd splits the text by spaces and between digits and letters, so "2kg" and "x3" become two tokens.
+func tokenizeQuickAdd(text string) []string {
+	tokens := make([]string, 0)
+	var token []rune
+	var digits bool
+
+	flush := func() {
+		if len(token) > 0 {
+			tokens = append(tokens, string(token))
+			token = token[:0]
+		}
+	}
+
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+
+		isDigit := unicode.IsDigit(r) || len(token) > 0 && digits && strings.ContainsRune(".,/", r)
+		if len(token) > 0 && isDigit != digits {
+			flush()
+		}
+		if r == '×' || r == '*' {
+			flush()
+			tokens = append(tokens, string(r))
+			continue
+		}
+
+		digits = isDigit
+		token = append(token, r)
+	}
+	flush()
+
+	return tokens
+}
+
+func isQuickAddNumber(token string) bool {
+	_, ok := parseQuickAddNumber(token)
+	return ok
+}
+
+// parseQuickAddNumber reads decimals with a point or comma, simple fractions and number words.
+// Words like "inf" or "nan" that ParseFloat accepts are not numbers here.
+func parseQuickAddNumber(token string) (float64, bool) {
+	if number, ok := numberWords[strings.ToLower(token)]; ok {
+		return number, true
+	}
+
+	if numerator, denominator, ok := strings.Cut(token, "/"); ok {
+		n, err := strconv.ParseFloat(numerator, 64)
+		if err != nil || !validQuickAddNumber(n) {
+			return 0, false
+		}
+		d, err := strconv.ParseFloat(denominator, 64)
+		if err != nil || !validQuickAddNumber(d) {
+			return 0, false
+		}
+		return n / d, true
+	}
+
+	number, err := strconv.ParseFloat(strings.Replace(token, ",", ".", 1), 64)
+	return number, err == nil && validQuickAddNumber(number)
+}
+
+// validQuickAddNumber reports whether the number is positive, finite and below maxQuickAddNumber.
+func validQuickAddNumber(number float64) bool {
+	return number > 0 && number < maxQuickAddNumber && !math.IsInf(number, 0) && !math.IsNaN(number)
+}
+
+func isQuickAddUnit(token string) bool {
+	_, ok := model.LookupUnit(strings.TrimSuffix(token, "."))
+	return ok && token != "" && token != "."
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuickAddEntry(t *testing.T) {
+	tests := []struct {
+		entry string
+		want  model.ShoppingItem
+		ok    bool
+	}{
+		{"milk", model.ShoppingItem{Title: "milk", Quantity: 1}, true},
+		{"2kg apples", model.ShoppingItem{Title: "apples", Quantity: 2, Unit: "kg"}, true},
+		{"2 kg apples", model.ShoppingItem{Title: "apples", Quantity: 2, Unit: "kg"}, true},
+		{"apples 2 kg", model.ShoppingItem{Title: "apples", Quantity: 2, Unit: "kg"}, true},
+		{"apples 2kg", model.ShoppingItem{Title: "apples", Quantity: 2, Unit: "kg"}, true},
+		{"3 x milk 1l", model.ShoppingItem{Title: "milk", Quantity: 3, Unit: "l"}, true},
+		{"3x eggs", model.ShoppingItem{Title: "eggs", Quantity: 3}, true},
+		{"eggs x3", model.ShoppingItem{Title: "eggs", Quantity: 3}, true},
+		{"eggs × 3", model.ShoppingItem{Title: "eggs", Quantity: 3}, true},
+		{"2 x 500 g cheese", model.ShoppingItem{Title: "cheese", Quantity: 1000, Unit: "g"}, true},
+		{"two dozen eggs", model.ShoppingItem{Title: "eggs", Quantity: 24}, true},
+		{"half kg cheese", model.ShoppingItem{Title: "cheese", Quantity: 0.5, Unit: "kg"}, true},
+		{"1/2 kg cheese", model.ShoppingItem{Title: "cheese", Quantity: 0.5, Unit: "kg"}, true},
+		{"3/4 l cream", model.ShoppingItem{Title: "cream", Quantity: 0.75, Unit: "l"}, true},
+		{"1.5 l water", model.ShoppingItem{Title: "water", Quantity: 1.5, Unit: "l"}, true},
+		{"2 packs of butter", model.ShoppingItem{Title: "butter", Quantity: 2, Unit: "pack"}, true},
+		{"pack of butter", model.ShoppingItem{Title: "butter", Quantity: 1, Unit: "pack"}, true},
+		{"bread (wholegrain)", model.ShoppingItem{Title: "bread", Description: "wholegrain", Quantity: 1}, true},
+		{"milk (2.5%) 2 l (cold)", model.ShoppingItem{Title: "milk", Description: "2.5%; cold", Quantity: 2, Unit: "l"}, true},
+
+		{"1,5 л молока", model.ShoppingItem{Title: "молока", Quantity: 1.5, Unit: "l"}, true},
+		{"два пакета молока", model.ShoppingItem{Title: "молока", Quantity: 2, Unit: "pack"}, true},
+		{"полтора кг картошки", model.ShoppingItem{Title: "картошки", Quantity: 1.5, Unit: "kg"}, true},
+		{"пачка масла", model.ShoppingItem{Title: "масла", Quantity: 1, Unit: "pack"}, true},
+		{"дюжина яиц", model.ShoppingItem{Title: "яиц", Quantity: 12}, true},
+		{"хлеб х2", model.ShoppingItem{Title: "хлеб", Quantity: 2}, true},
+		{"сыр 300 гр", model.ShoppingItem{Title: "сыр", Quantity: 300, Unit: "g"}, true},
+
+		// numbers that are not finite or too large stay in the title
+		{"inf milk", model.ShoppingItem{Title: "inf milk", Quantity: 1}, true},
+		{"NaN milk", model.ShoppingItem{Title: "NaN milk", Quantity: 1}, true},
+		{"1/0 eggs", model.ShoppingItem{Title: "1/0 eggs", Quantity: 1}, true},
+		{"2000000000 eggs", model.ShoppingItem{Title: "2000000000 eggs", Quantity: 1}, true},
+
+		{"2 kg", model.ShoppingItem{Quantity: 2, Unit: "kg"}, false},
+		{"(note)", model.ShoppingItem{Description: "note"}, false},
+		// a multiplied quantity out of range is rejected
+		{"100000 x 100000 x eggs", model.ShoppingItem{Title: "eggs", Quantity: 1e10}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseQuickAddEntry(tt.entry)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseQuickAddEntry(%q) = %+v, %v, want %+v, %v", tt.entry, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseQuickAdd(t *testing.T) {
+	items, unparsed := parseQuickAdd("2kg apples, 3 x milk 1l; bread (wholegrain)\n1,5 л молока\n\n2 kg, ;")
+
+	want := []model.ShoppingItem{
+		{Title: "apples", Quantity: 2, Unit: "kg"},
+		{Title: "milk", Quantity: 3, Unit: "l"},
+		{Title: "bread", Description: "wholegrain", Quantity: 1},
+		{Title: "молока", Quantity: 1.5, Unit: "l"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items are %+v, want %+v", items, want)
+	}
+	if !reflect.DeepEqual(unparsed, []string{"2 kg"}) {
+		t.Errorf("unparsed entries are %q, want [\"2 kg\"]", unparsed)
+	}
+}
+
+func TestParseQuickAddNumber(t *testing.T) {
+	tests := []struct {
+		token string
+		want  float64
+		ok    bool
+	}{
+		{"3", 3, true},
+		{"1.5", 1.5, true},
+		{"1,5", 1.5, true},
+		{"3/4", 0.75, true},
+		{"Two", 2, true},
+		{"дюжина", 12, true},
+		{"пол", 0.5, true},
+
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"1/0", 0, false},
+		{"inf", 0, false},
+		{"+Inf", 0, false},
+		{"nan", 0, false},
+		{"1e9", 0, false},
+		{"1e400", 0, false},
+		{"milk", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseQuickAddNumber(tt.token)
+		if ok != tt.ok || ok && got != tt.want {
+			t.Errorf("parseQuickAddNumber(%q) = %v, %v, want %v, %v", tt.token, got, ok, tt.want, tt.ok)
+		}
+	}
+}
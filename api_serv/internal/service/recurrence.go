@@ -0,0 +1,252 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+
+	// maxEmptyPeriods stops the search for rules that can never occur again,
+	// e.g. the 31st of every February, after that many periods in a row without occurrences.
+	maxEmptyPeriods = 1000
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// recurrence is the supported subset of an RFC 5545 RRULE: FREQ (DAILY, WEEKLY, MONTHLY),
+// INTERVAL, BYDAY and WKST for weekly rules, BYMONTHDAY for monthly rules (-1 is the last day), COUNT and UNTIL.
+// Occurrences happen at the time of day of the start. Weeks start on Monday unless WKST says otherwise.
+type recurrence struct {
+	freq       string
+	interval   int
+	byDay      []time.Weekday
+	weekStart  time.Weekday
+	byMonthDay []int
+	count      int
+	until      time.Time
+}
+
+func parseRecurrence(rule string) (recurrence, error) {
+	r := recurrence{interval: 1, weekStart: time.Monday}
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return r, errors.New("invalid rule part " + part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(value)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = errors.New("interval must be positive")
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return r, errors.New("invalid day " + day)
+				}
+				r.byDay = append(r.byDay, weekday)
+			}
+		case "WKST":
+			weekday, ok := weekdays[strings.ToUpper(value)]
+			if !ok {
+				return r, errors.New("invalid week start " + value)
+			}
+			r.weekStart = weekday
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return r, errors.New("invalid month day " + day)
+				}
+				r.byMonthDay = append(r.byMonthDay, monthDay)
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err == nil && r.count < 1 {
+				err = errors.New("count must be positive")
+			}
+		case "UNTIL":
+			r.until, err = parseUntil(value)
+		default:
+			return r, errors.New("unsupported rule part " + key)
+		}
+		if err != nil {
+			return r, errors.New("invalid " + strings.ToUpper(key) + ": " + err.Error())
+		}
+	}
+
+	switch r.freq {
+	case freqDaily, freqWeekly, freqMonthly:
+	case "":
+		return r, errors.New("rule has no FREQ")
+	default:
+		return r, errors.New("unsupported FREQ " + r.freq)
+	}
+	if len(r.byDay) > 0 && r.freq != freqWeekly {
+		return r, errors.New("BYDAY is supported for weekly rules only")
+	}
+	if len(r.byMonthDay) > 0 && r.freq != freqMonthly {
+		return r, errors.New("BYMONTHDAY is supported for monthly rules only")
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.New("unknown date format")
+}
+
+// next returns the first occurrence after the given time, or false if the rule has ended.
+func (r recurrence) next(start, after time.Time) (time.Time, bool) {
+	n := 0
+	period := 0
+	if r.count == 0 {
+		// without COUNT the occurrences before after need not be counted
+		period = r.periodsBefore(start, after)
+	}
+
+	for empty := 0; empty < maxEmptyPeriods; period++ {
+		occurrences := r.periodOccurrences(start, period)
+		if len(occurrences) == 0 || occurrences[len(occurrences)-1].Before(start) {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, t := range occurrences {
+			if t.Before(start) {
+				continue
+			}
+			n++
+			if r.count > 0 && n > r.count || !r.until.IsZero() && t.After(r.until) {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// periodsBefore returns the number of whole periods of the rule between start and after
+// that end before after, so their occurrences can be skipped.
+func (r recurrence) periodsBefore(start, after time.Time) int {
+	after = after.In(start.Location())
+	if !after.After(start) {
+		return 0
+	}
+
+	// whole days between the dates, independent of daylight saving time changes
+	days := int(time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC).Sub(
+		time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+
+	var periods int
+	switch r.freq {
+	case freqDaily:
+		periods = days / r.interval
+	case freqWeekly:
+		periods = days / 7 / r.interval
+	case freqMonthly:
+		months := (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+		periods = months / r.interval
+	}
+
+	// the period containing after is searched too
+	return max(periods-1, 0)
+}
+
+// upcoming returns up to limit occurrences after the given time.
+func (r recurrence) upcoming(start, after time.Time, limit int) []time.Time {
+	occurrences := make([]time.Time, 0, limit)
+	for len(occurrences) < limit {
+		t, ok := r.next(start, after)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, t)
+		after = t
+	}
+
+	return occurrences
+}
+
+// periodOccurrences returns the sorted candidate times of the period-th day, week or month after start.
+func (r recurrence) periodOccurrences(start time.Time, period int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var occurrences []time.Time
+	switch r.freq {
+	case freqDaily:
+		occurrences = append(occurrences, at(start.Year(), start.Month(), start.Day()+period*r.interval))
+	case freqWeekly:
+		days := r.byDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		weekStart := start.Day() - r.weekdayOffset(start.Weekday()) + period*r.interval*7
+		for _, day := range days {
+			occurrences = append(occurrences, at(start.Year(), start.Month(), weekStart+r.weekdayOffset(day)))
+		}
+	case freqMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(period*r.interval), 1, 0, 0, 0, 0, start.Location())
+		daysInMonth := month.AddDate(0, 1, -1).Day()
+		days := r.byMonthDay
+		if len(days) == 0 {
+			days = []int{start.Day()}
+		}
+		for _, day := range days {
+			if day < 0 {
+				day = daysInMonth + day + 1
+			}
+			if day >= 1 && day <= daysInMonth {
+				occurrences = append(occurrences, at(month.Year(), month.Month(), day))
+			}
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+
+	unique := occurrences[:0]
+	for i, t := range occurrences {
+		if i == 0 || !t.Equal(occurrences[i-1]) {
+			unique = append(unique, t)
+		}
+	}
+
+	return unique
+}
+
+// weekdayOffset returns the number of days from the start of the week to the weekday.
+func (r recurrence) weekdayOffset(day time.Weekday) int {
+	return (int(day) - int(r.weekStart) + 7) % 7
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceUpcoming(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2025-01-06 is a Monday
+	monday := date(2025, time.January, 6, 9)
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		limit int
+		want  []time.Time
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			start: monday,
+			after: monday.Add(-time.Hour),
+			limit: 3,
+			want:  []time.Time{monday, date(2025, time.January, 8, 9), date(2025, time.January, 10, 9)},
+		},
+		{
+			name:  "daily starts after the given time",
+			rule:  "RRULE:FREQ=DAILY",
+			start: monday,
+			after: monday,
+			limit: 2,
+			want:  []time.Time{date(2025, time.January, 7, 9), date(2025, time.January, 8, 9)},
+		},
+		{
+			name:  "daily keeps the local time over daylight saving time",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, time.March, 8, 9, 0, 0, 0, newYork),
+			after: time.Date(2025, time.March, 8, 10, 0, 0, 0, newYork),
+			limit: 2,
+			want: []time.Time{time.Date(2025, time.March, 9, 9, 0, 0, 0, newYork),
+				time.Date(2025, time.March, 10, 9, 0, 0, 0, newYork)},
+		},
+		{
+			name:  "daily rule started decades ago",
+			rule:  "FREQ=DAILY",
+			start: date(1990, time.January, 1, 9),
+			after: date(2026, time.October, 19, 12),
+			limit: 1,
+			want:  []time.Time{date(2026, time.October, 20, 9)},
+		},
+		{
+			name:  "daily rule with a count reaching decades ahead",
+			rule:  "FREQ=DAILY;COUNT=20000",
+			start: date(1990, time.January, 1, 9),
+			after: date(2026, time.October, 19, 12),
+			limit: 1,
+			want:  []time.Time{date(2026, time.October, 20, 9)},
+		},
+		{
+			name:  "weekly on the day of the start",
+			rule:  "FREQ=WEEKLY",
+			start: monday,
+			after: monday,
+			limit: 2,
+			want:  []time.Time{date(2025, time.January, 13, 9), date(2025, time.January, 20, 9)},
+		},
+		{
+			name:  "weekly on several days",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			start: monday,
+			after: monday.Add(-time.Hour),
+			limit: 4,
+			want: []time.Time{monday, date(2025, time.January, 8, 9), date(2025, time.January, 10, 9),
+				date(2025, time.January, 13, 9)},
+		},
+		{
+			name:  "weeks start on monday by default",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU",
+			start: monday,
+			after: monday.Add(-time.Hour),
+			limit: 4,
+			want: []time.Time{monday, date(2025, time.January, 12, 9), date(2025, time.January, 20, 9),
+				date(2025, time.January, 26, 9)},
+		},
+		{
+			name:  "weeks start on sunday with WKST",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU;WKST=SU",
+			start: monday,
+			after: monday.Add(-time.Hour),
+			limit: 3,
+			want:  []time.Time{monday, date(2025, time.January, 19, 9), date(2025, time.January, 20, 9)},
+		},
+		{
+			name:  "weekly rule started years ago",
+			rule:  "FREQ=WEEKLY;INTERVAL=3;BYDAY=TU",
+			start: date(2000, time.January, 4, 9),
+			after: date(2000, time.January, 4, 9).AddDate(0, 0, 21*1000-1),
+			limit: 1,
+			want:  []time.Time{date(2000, time.January, 4, 9).AddDate(0, 0, 21*1000)},
+		},
+		{
+			name:  "monthly on the last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: date(2025, time.January, 15, 9),
+			after: date(2025, time.January, 15, 9),
+			limit: 3,
+			want: []time.Time{date(2025, time.January, 31, 9), date(2025, time.February, 28, 9),
+				date(2025, time.March, 31, 9)},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, time.January, 31, 9),
+			after: date(2025, time.January, 31, 9),
+			limit: 2,
+			want:  []time.Time{date(2025, time.March, 31, 9), date(2025, time.May, 31, 9)},
+		},
+		{
+			name:  "count limits occurrences",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: monday,
+			after: monday.Add(-time.Hour),
+			limit: 5,
+			want:  []time.Time{monday, date(2025, time.January, 7, 9), date(2025, time.January, 8, 9)},
+		},
+		{
+			name:  "until limits occurrences",
+			rule:  "FREQ=WEEKLY;UNTIL=20250120T090000Z",
+			start: monday,
+			after: monday,
+			limit: 5,
+			want:  []time.Time{date(2025, time.January, 13, 9), date(2025, time.January, 20, 9)},
+		},
+		{
+			name:  "rule that never occurs",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+			start: date(2025, time.February, 1, 9),
+			after: date(2025, time.February, 1, 9),
+			limit: 1,
+			want:  []time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("parseRecurrence(%q): %v", tt.rule, err)
+			}
+
+			got := rule.upcoming(tt.start, tt.after, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("occurrence %d is %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	rules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;WKST=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ",
+	}
+
+	for _, rule := range rules {
+		if _, err := parseRecurrence(rule); err == nil {
+			t.Errorf("parseRecurrence(%q) must fail", rule)
+		}
+	}
+}
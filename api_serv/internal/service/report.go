@@ -0,0 +1,22 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+)
+
+type ReportService struct {
+	repo repository.Report
+}
+
+func NewReportService(repo repository.Report) *ReportService {
+	return &ReportService{repo: repo}
+}
+
+func (s *ReportService) Spending(userId int, filter model.SpendingFilter) ([]model.SpendingRow, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Spending(userId, filter)
+}
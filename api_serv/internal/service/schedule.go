@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"time"
+)
+
+const maxPreviewCount = 50
+
+type ScheduleService struct {
+	repo         repository.Schedule
+	templateRepo repository.Template
+	listRepo     repository.List
+}
+
+func NewScheduleService(repo repository.Schedule, templateRepo repository.Template, listRepo repository.List) *ScheduleService {
+	return &ScheduleService{repo: repo, templateRepo: templateRepo, listRepo: listRepo}
+}
+
+func (s *ScheduleService) Create(userId int, schedule model.Schedule) (int, error) {
+	schedule.Enabled = true
+	if err := s.prepare(userId, &schedule); err != nil {
+		return 0, err
+	}
+	return s.repo.Create(userId, schedule)
+}
+
+func (s *ScheduleService) GetAll(userId int) ([]model.Schedule, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *ScheduleService) GetById(userId, scheduleId int) (model.Schedule, error) {
+	return s.repo.GetById(userId, scheduleId)
+}
+
+// Update changes the schedule and recalculates its next run. ListId 0 makes the schedule create new lists again.
+func (s *ScheduleService) Update(userId, scheduleId int, input model.UpdateScheduleInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	schedule, err := s.repo.GetById(userId, scheduleId)
+	if err != nil {
+		return err
+	}
+
+	if input.TemplateId != nil {
+		schedule.TemplateId = *input.TemplateId
+	}
+	if input.ListId != nil {
+		schedule.ListId = input.ListId
+		if *input.ListId == 0 {
+			schedule.ListId = nil
+		}
+	}
+	if input.Title != nil {
+		schedule.Title = *input.Title
+	}
+	if input.Rule != nil {
+		schedule.Rule = *input.Rule
+	}
+	if input.Start != nil {
+		schedule.Start = *input.Start
+	}
+	if input.Timezone != nil {
+		schedule.Timezone = *input.Timezone
+	}
+	if input.Enabled != nil {
+		schedule.Enabled = *input.Enabled
+	}
+
+	if err = s.prepare(userId, &schedule); err != nil {
+		return err
+	}
+	return s.repo.Update(userId, schedule)
+}
+
+func (s *ScheduleService) Delete(userId, scheduleId int) error {
+	return s.repo.Delete(userId, scheduleId)
+}
+
+// Preview returns the next count occurrences of the schedule.
+func (s *ScheduleService) Preview(userId, scheduleId, count int) ([]time.Time, error) {
+	if count < 1 || count > maxPreviewCount {
+		return nil, errors.New("count is out of range")
+	}
+
+	schedule, err := s.repo.GetById(userId, scheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, start, err := scheduleRecurrence(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule.upcoming(start, time.Now(), count), nil
+}
+
+// prepare validates the schedule and calculates its next run.
+func (s *ScheduleService) prepare(userId int, schedule *model.Schedule) error {
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+
+	if _, err := s.templateRepo.GetById(userId, schedule.TemplateId); err != nil {
+		return err
+	}
+	if schedule.ListId != nil {
+		if _, err := s.listRepo.GetById(userId, *schedule.ListId); err != nil {
+			return err
+		}
+	}
+
+	rule, start, err := scheduleRecurrence(*schedule)
+	if err != nil {
+		return err
+	}
+
+	schedule.NextRun = nil
+	if next, ok := rule.next(start, time.Now()); ok && schedule.Enabled {
+		schedule.NextRun = &next
+	}
+
+	return nil
+}
+
+// scheduleRecurrence parses the schedule rule and returns its start in the schedule time zone.
+func scheduleRecurrence(schedule model.Schedule) (recurrence, time.Time, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return recurrence{}, time.Time{}, errors.New("unknown time zone " + schedule.Timezone)
+	}
+
+	rule, err := parseRecurrence(schedule.Rule)
+	if err != nil {
+		return recurrence{}, time.Time{}, err
+	}
+
+	return rule, schedule.Start.In(loc), nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const schedulerBatchSize = 100
+
+// Scheduler runs due schedules in the background. Only the replica holding the
+// leader lock processes them, the others keep trying to take the lock over.
+type Scheduler struct {
+	repo     repository.Schedule
+	leader   repository.Leader
+	interval time.Duration
+}
+
+func NewScheduler(repos *repository.Repository, interval time.Duration) *Scheduler {
+	return &Scheduler{repo: repos.Schedule, leader: repos.Leader, interval: interval}
+}
+
+// Run processes schedules until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			if err := s.leader.Release(); err != nil {
+				logrus.Errorf("failed to release scheduler lock: %s", err.Error())
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	leader, err := s.leader.TryAcquire(ctx)
+	if err != nil {
+		logrus.Errorf("failed to acquire scheduler lock: %s", err.Error())
+		return
+	}
+	if !leader {
+		return
+	}
+
+	now := time.Now()
+	due, err := s.repo.GetDue(now, schedulerBatchSize)
+	if err != nil {
+		logrus.Errorf("failed to get due schedules: %s", err.Error())
+		return
+	}
+
+	for _, schedule := range due {
+		s.run(schedule, now)
+	}
+}
+
+// run runs the schedule and advances it to its next occurrence after now in one transaction.
+// Occurrences missed while no replica was running are run once. A failed run leaves
+// the schedule due, so it is run again on the next tick.
+func (s *Scheduler) run(schedule model.Schedule, now time.Time) {
+	rule, start, err := scheduleRecurrence(schedule)
+	if err != nil {
+		// a schedule without a next run is never due again
+		logrus.Errorf("schedule %d is stopped: %s", schedule.Id, err.Error())
+		if _, err = s.repo.Advance(schedule.Id, *schedule.NextRun, nil, now); err != nil {
+			logrus.Errorf("failed to stop schedule %d: %s", schedule.Id, err.Error())
+		}
+		return
+	}
+
+	var next *time.Time
+	if t, ok := rule.next(start, now); ok {
+		next = &t
+	}
+
+	// a schedule advanced meanwhile by another replica or the user is not run
+	if _, err = s.repo.Run(schedule, next, now); err != nil {
+		logrus.Errorf("failed to run schedule %d: %s", schedule.Id, err.Error())
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(userId int, filter model.SearchFilter) (model.SearchPage, error) {
+	if err := filter.Validate(); err != nil {
+		return model.SearchPage{}, err
+	}
+
+	return s.repo.Search(userId, filter)
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/elecshen/shopping_list/internal/repository"
+	"os"
+	"time"
 )
 
 type Authorization interface {
@@ -13,30 +15,140 @@ type Authorization interface {
 
 type List interface {
 	Create(userId int, list model.ShoppingList) (int, error)
-	GetAll(userId int) ([]model.ShoppingList, error)
+	GetAll(userId int, archived *bool) ([]model.ShoppingList, error)
 	GetById(userId, listId int) (model.ShoppingList, error)
-	Update(userId, listId int, input model.UpdateListInput) error
-	Delete(userId, listId int) error
+	Update(userId, listId int, input model.UpdateListInput, version int) error
+	Delete(userId, listId int, version int) error
+	SetArchived(userId, listId int, archived bool) error
+	Reorder(userId int, ids []int) error
+	Duplicate(userId, listId int, input model.DuplicateListInput) (int, error)
+	Merge(userId int, input model.MergeListsInput) error
+	Split(userId, listId int, input model.SplitListInput) (int, error)
+	BudgetWarning(userId, listId int) (string, error)
 }
 
 type Item interface {
 	Create(userId, listId int, item model.ShoppingItem) (int, error)
+	QuickAdd(userId, listId int, text string) (model.QuickAddResult, error)
 	GetAll(userId, listId int) ([]model.ShoppingItem, error)
+	GetAllByCategory(userId, listId int) ([]model.ItemCategoryGroup, error)
+	GetAllInStoreOrder(userId, listId int) ([]model.ShoppingItem, error)
 	GetById(userId, itemId int) (model.ShoppingItem, error)
-	Update(userId, itemId int, input model.UpdateItemInput) error
-	Delete(userId, itemId int) error
+	Update(userId, itemId int, input model.UpdateItemInput, version int) error
+	Delete(userId, itemId int, version int) error
+	SetPosition(userId, itemId int, input model.ItemPositionInput, version int) (string, error)
+	Move(userId, listId int, itemIds []int) error
+	Copy(userId, listId int, itemIds []int) ([]int, error)
+	Bulk(userId, listId int, input model.BulkItemsInput) ([]model.BulkItemResult, error)
+}
+
+type Store interface {
+	Create(userId int, store model.Store) (int, error)
+	GetAll(userId int) ([]model.Store, error)
+	GetById(userId, storeId int) (model.Store, error)
+	Update(userId, storeId int, input model.UpdateStoreInput) error
+	Delete(userId, storeId int) error
+}
+
+type Suggestion interface {
+	GetAll(userId, listId int) ([]model.Suggestion, error)
+	Add(userId, listId int, input model.AddSuggestionInput) (int, error)
+}
+
+type Product interface {
+	GetAll(userId int) ([]model.Product, error)
+	GetPrices(userId, productId int) (model.ProductPrices, error)
+}
+
+type Trip interface {
+	Start(userId, listId int, input model.StartTripInput) (int, error)
+	GetAll(userId int) ([]model.Trip, error)
+	GetById(userId, tripId int) (model.Trip, error)
+	Finish(userId, tripId int, input model.FinishTripInput) error
+}
+
+type Autocomplete interface {
+	Complete(userId int, query string, limit int) ([]model.Completion, error)
+}
+
+type Search interface {
+	Search(userId int, filter model.SearchFilter) (model.SearchPage, error)
+}
+
+type Report interface {
+	Spending(userId int, filter model.SpendingFilter) ([]model.SpendingRow, error)
+}
+
+type Snapshot interface {
+	Create(userId, listId int) (int, error)
+	GetAll(userId, listId int) ([]model.ListSnapshot, error)
+	GetById(userId, snapshotId int) (model.ListSnapshot, error)
+	Diff(userId, fromId, toId int) (model.ListDiff, error)
+	Restore(userId, snapshotId int) (model.SnapshotRestore, error)
+}
+
+type Sync interface {
+	Pull(userId, since int) (model.SyncChanges, error)
+	Push(userId int, mutations []model.SyncMutation) []model.SyncResult
+}
+
+type Template interface {
+	Create(userId int, input model.CreateTemplateInput) (int, error)
+	GetAll(userId int) ([]model.ListTemplate, error)
+	GetById(userId, templateId int) (model.ListTemplate, error)
+	Update(userId, templateId int, input model.UpdateTemplateInput) error
+	Delete(userId, templateId int) error
+	Instantiate(userId, templateId int, input model.InstantiateTemplateInput) (int, error)
+}
+
+type Schedule interface {
+	Create(userId int, schedule model.Schedule) (int, error)
+	GetAll(userId int) ([]model.Schedule, error)
+	GetById(userId, scheduleId int) (model.Schedule, error)
+	Update(userId, scheduleId int, input model.UpdateScheduleInput) error
+	Delete(userId, scheduleId int) error
+	Preview(userId, scheduleId, count int) ([]time.Time, error)
 }
 
 type Service struct {
 	Authorization
 	List
 	Item
+	Suggestion
+	Autocomplete
+	Search
+	Store
+	Product
+	Trip
+	Report
+	Snapshot
+	Sync
+	Template
+	Schedule
 }
 
 func NewService(repos *repository.Repository) *Service {
+	node, err := os.Hostname()
+	if err != nil {
+		node = "api"
+	}
+	clock := NewHybridClock(node)
+	items := NewShoppingItemService(repos.Item, repos.List, repos.Category, repos.Store, repos.Product, clock)
+
 	return &Service{
 		Authorization: NewAuthService(repos),
-		List:          NewShoppingListService(repos.List),
-		Item:          NewShoppingItemService(repos.Item, repos.List),
+		List:          NewShoppingListService(repos.List, repos.Store),
+		Item:          items,
+		Suggestion:    NewSuggestionService(repos.Purchase, repos.List, repos.Item, items),
+		Autocomplete:  NewAutocompleteService(repos.ItemHistory),
+		Search:        NewSearchService(repos.Search),
+		Product:       NewProductService(repos.Product),
+		Trip:          NewTripService(repos.Trip, repos.List, repos.Store, clock),
+		Report:        NewReportService(repos.Report),
+		Store:         NewStoreService(repos.Store),
+		Snapshot:      NewSnapshotService(repos.Snapshot, repos.List, repos.Item),
+		Sync:          NewSyncService(repos.Sync, repos.List, repos.Item, repos.ItemState, clock),
+		Template:      NewTemplateService(repos.Template, repos.List, repos.Item),
+		Schedule:      NewScheduleService(repos.Schedule, repos.Template, repos.List),
 	}
 }
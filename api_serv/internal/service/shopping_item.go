@@ -1,41 +1,438 @@
 package service
 
 import (
+	"database/sql"
+	"errors"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/elecshen/shopping_list/internal/repository"
+	"github.com/sirupsen/logrus"
+	"strings"
 )
 
 type ShoppingItemService struct {
-	repo     repository.Item
-	listRepo repository.List
+	repo         repository.Item
+	listRepo     repository.List
+	categoryRepo repository.Category
+	storeRepo    repository.Store
+	productRepo  repository.Product
+	clock        *HybridClock
 }
 
-func NewShoppingItemService(repo repository.Item, listRepo repository.List) *ShoppingItemService {
-	return &ShoppingItemService{repo: repo, listRepo: listRepo}
+func NewShoppingItemService(repo repository.Item, listRepo repository.List, categoryRepo repository.Category,
+	storeRepo repository.Store, productRepo repository.Product, clock *HybridClock) *ShoppingItemService {
+	return &ShoppingItemService{repo: repo, listRepo: listRepo, categoryRepo: categoryRepo, storeRepo: storeRepo,
+		productRepo: productRepo, clock: clock}
 }
 
 func (s *ShoppingItemService) Create(userId, listId int, item model.ShoppingItem) (int, error) {
-	_, err := s.listRepo.GetById(userId, listId)
+	list, err := s.listRepo.GetById(userId, listId)
 	if err != nil {
 		// list does not exist or does not belong to user
 		return 0, err
 	}
 
-	return s.repo.Create(listId, item)
+	return s.add(userId, list, item)
+}
+
+// QuickAdd creates items parsed from free text in one transaction and returns them with the entries it could not parse.
+func (s *ShoppingItemService) QuickAdd(userId, listId int, text string) (model.QuickAddResult, error) {
+	result := model.QuickAddResult{Items: make([]model.ShoppingItem, 0)}
+	list, err := s.listRepo.GetById(userId, listId)
+	if err != nil {
+		return result, err
+	}
+
+	var items []model.ShoppingItem
+	items, result.Unparsed = parseQuickAdd(text)
+	for i := range items {
+		if err = s.prepare(userId, list, &items[i]); err != nil {
+			return result, err
+		}
+	}
+
+	var ids []int
+	for attempt := 0; attempt < mergeAttempts; attempt++ {
+		ids, err = s.addAll(userId, list, items)
+		if !errors.Is(err, model.ErrVersionMismatch) {
+			break
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	for _, id := range ids {
+		created, err := s.repo.GetById(userId, id)
+		if err != nil {
+			return result, err
+		}
+		result.Items = append(result.Items, created)
+	}
+
+	// the items are saved already, the result goes without the warning if it fails
+	if result.Warning, err = budgetWarning(s.listRepo, userId, listId); err != nil {
+		logrus.Errorf("failed to check the budget of list %d: %s", listId, err.Error())
+	}
+
+	return result, nil
+}
+
+// add creates the item in the list the user has access to. A merge that loses the race
+// to a concurrent change of the item is retried.
+func (s *ShoppingItemService) add(userId int, list model.ShoppingList, item model.ShoppingItem) (int, error) {
+	if err := s.prepare(userId, list, &item); err != nil {
+		return 0, err
+	}
+
+	var id int
+	var err error
+	for attempt := 0; attempt < mergeAttempts; attempt++ {
+		id, err = s.addOnce(userId, list, item)
+		if !errors.Is(err, model.ErrVersionMismatch) {
+			break
+		}
+	}
+
+	return id, err
+}
+
+// addOnce creates the prepared item or merges it into the item it was read with.
+func (s *ShoppingItemService) addOnce(userId int, list model.ShoppingList, item model.ShoppingItem) (int, error) {
+	// the same product added again in a compatible unit increases the quantity of the unchecked item
+	items, err := s.repo.GetAll(userId, list.Id)
+	if err != nil {
+		return 0, err
+	}
+	i, err := mergeInto(items, item)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return s.repo.Create(list.Id, item)
+	}
+
+	input := mergedInput(items[i], item.EstimatedPrice > 0)
+	input.Clocks = stampItemFields(input, s.clock.Now())
+
+	return items[i].Id, s.repo.Update(userId, items[i].Id, input, items[i].Version)
+}
+
+// addAll adds the prepared items to the list like add, all of them in one transaction,
+// and returns the ID of the item every one of them has been created as or merged into.
+func (s *ShoppingItemService) addAll(userId int, list model.ShoppingList, items []model.ShoppingItem) ([]int, error) {
+	existing, err := s.repo.GetAll(userId, list.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// merges see the items created earlier in the text as well
+	known := len(existing)
+	all := existing
+	targets := make([]int, len(items))
+	priced := make(map[int]bool)
+	for j, item := range items {
+		i, err := mergeInto(all, item)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			all = append(all, item)
+			i = len(all) - 1
+		} else if i < known {
+			priced[i] = priced[i] || item.EstimatedPrice > 0
+		}
+		targets[j] = i
+	}
+
+	ts := s.clock.Now()
+	changes := make([]model.ItemUpdate, 0, len(priced))
+	for i := 0; i < known; i++ {
+		if _, ok := priced[i]; !ok {
+			continue
+		}
+		input := mergedInput(all[i], priced[i])
+		input.Clocks = stampItemFields(input, ts)
+		changes = append(changes, model.ItemUpdate{Id: all[i].Id, Version: all[i].Version, Input: input})
+	}
+
+	created, err := s.repo.AddAll(userId, list.Id, changes, all[known:])
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(items))
+	for j, i := range targets {
+		if i < known {
+			ids[j] = all[i].Id
+		} else {
+			ids[j] = created[i-known]
+		}
+	}
+
+	return ids, nil
+}
+
+// prepare checks the new item and fills in its unit, category and estimated price.
+func (s *ShoppingItemService) prepare(userId int, list model.ShoppingList, item *model.ShoppingItem) error {
+	if err := normalizeItem(item); err != nil {
+		return err
+	}
+	if err := s.classify(userId, item); err != nil {
+		return err
+	}
+
+	return estimatePrice(s.productRepo, userId, list, item)
+}
+
+// mergeInto adds the quantity and the estimated price of the item to the unchecked item of the same
+// product in a compatible unit and returns its index in items, or -1 if there is none.
+func mergeInto(items []model.ShoppingItem, item model.ShoppingItem) (int, error) {
+	for i, existing := range items {
+		if existing.Checked || !sameProduct(existing.Title, item.Title) || !model.UnitsCompatible(existing.Unit, item.Unit) {
+			continue
+		}
+
+		quantity, err := model.ConvertQuantity(item.Quantity, item.Unit, existing.Unit)
+		if err != nil {
+			return -1, err
+		}
+		items[i].Quantity = model.RoundQuantity(existing.Quantity + quantity)
+		items[i].EstimatedPrice += item.EstimatedPrice
+
+		return i, nil
+	}
+
+	return -1, nil
+}
+
+// mergedInput returns the update storing the quantity and, if it has changed, the estimated price of the merged item.
+func mergedInput(merged model.ShoppingItem, priced bool) model.UpdateItemInput {
+	input := model.UpdateItemInput{Quantity: &merged.Quantity}
+	if priced {
+		input.EstimatedPrice = &merged.EstimatedPrice
+	}
+
+	return input
 }
 
 func (s *ShoppingItemService) GetAll(userId, listId int) ([]model.ShoppingItem, error) {
 	return s.repo.GetAll(userId, listId)
 }
 
+// GetAllByCategory returns the items of the list grouped by category.
+func (s *ShoppingItemService) GetAllByCategory(userId, listId int) ([]model.ItemCategoryGroup, error) {
+	items, err := s.repo.GetAll(userId, listId)
+	if err != nil {
+		return nil, err
+	}
+	return groupByCategory(items), nil
+}
+
+// GetAllInStoreOrder returns the items of the list in the walking order of the store the list is assigned to.
+func (s *ShoppingItemService) GetAllInStoreOrder(userId, listId int) ([]model.ShoppingItem, error) {
+	list, err := s.listRepo.GetById(userId, listId)
+	if err != nil {
+		return nil, err
+	}
+	if list.StoreId == nil {
+		return nil, errors.New("list is not assigned to a store")
+	}
+
+	store, err := s.storeRepo.GetById(userId, *list.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	ranks, err := s.storeRepo.GetRanks(store.Id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetAll(userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	sortByStore(items, store, ranks)
+	return items, nil
+}
+
 func (s *ShoppingItemService) GetById(userId, itemId int) (model.ShoppingItem, error) {
 	return s.repo.GetById(userId, itemId)
 }
 
-func (s *ShoppingItemService) Delete(userId, itemId int) error {
-	return s.repo.Delete(userId, itemId)
+func (s *ShoppingItemService) Delete(userId, itemId int, version int) error {
+	return s.repo.Delete(userId, itemId, version)
+}
+
+func (s *ShoppingItemService) Update(userId, itemId int, input model.UpdateItemInput, version int) error {
+	if err := normalizeUpdate(&input); err != nil {
+		return err
+	}
+	// stamp the write so offline edits made before it lose to it on sync
+	input.Clocks = stampItemFields(input, s.clock.Now())
+	update := s.repo.Update
+	if input.Checked != nil && *input.Checked {
+		// only items checked off one by one show the walking order of the store
+		update = s.repo.CheckOff
+	}
+	if err := update(userId, itemId, input, version); err != nil {
+		return err
+	}
+
+	if input.Category == nil {
+		return nil
+	}
+	// remember the category the user has chosen for the product
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		return err
+	}
+	return s.categoryRepo.Remember(userId, categoryKey(item.Title), *input.Category)
+}
+
+// SetPosition places the item between its neighbour items and returns its new position.
+func (s *ShoppingItemService) SetPosition(userId, itemId int, input model.ItemPositionInput, version int) (string, error) {
+	if err := input.Validate(); err != nil {
+		return "", err
+	}
+
+	return s.repo.SetPosition(userId, itemId, input, version)
+}
+
+func (s *ShoppingItemService) Move(userId, listId int, itemIds []int) error {
+	if err := (model.TransferItemsInput{ListId: listId, ItemIds: itemIds}).Validate(); err != nil {
+		return err
+	}
+	if _, err := s.listRepo.GetById(userId, listId); err != nil {
+		// target list does not exist or does not belong to user
+		return err
+	}
+	return s.repo.Move(userId, listId, itemIds)
+}
+
+func (s *ShoppingItemService) Copy(userId, listId int, itemIds []int) ([]int, error) {
+	if err := (model.TransferItemsInput{ListId: listId, ItemIds: itemIds}).Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.listRepo.GetById(userId, listId); err != nil {
+		return nil, err
+	}
+	return s.repo.Copy(userId, listId, itemIds)
+}
+
+func (s *ShoppingItemService) Bulk(userId, listId int, input model.BulkItemsInput) ([]model.BulkItemResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := s.listRepo.GetById(userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	// stamp every write with one clock reading, the operations form a single change
+	ts := s.clock.Now()
+	ops := make([]model.BulkItemOperation, len(input.Operations))
+	for i, op := range input.Operations {
+		switch op.Op {
+		case model.BulkOpCheckAll, model.BulkOpUncheckAll:
+			checked := op.Op == model.BulkOpCheckAll
+			op.Patch = &model.UpdateItemInput{Checked: &checked}
+		case model.BulkOpCreate:
+			items := make([]model.ShoppingItem, len(op.Items))
+			for j, item := range op.Items {
+				if err := normalizeItem(&item); err != nil {
+					return nil, err
+				}
+				if err := s.classify(userId, &item); err != nil {
+					return nil, err
+				}
+				if err := estimatePrice(s.productRepo, userId, list, &item); err != nil {
+					return nil, err
+				}
+				items[j] = item
+			}
+			op.Items = items
+		}
+		if op.Patch != nil {
+			patch := *op.Patch
+			if err := normalizeUpdate(&patch); err != nil {
+				return nil, err
+			}
+			patch.Clocks = stampItemFields(patch, ts)
+			op.Patch = &patch
+		}
+		ops[i] = op
+	}
+
+	return s.repo.Bulk(userId, listId, ops)
+}
+
+// normalizeItem checks the prices and defaults the quantity of a new item to a single unit and the unit to pieces.
+func normalizeItem(item *model.ShoppingItem) error {
+	if item.Quantity < 0 {
+		return errors.New("item quantity is negative")
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+	if item.EstimatedPrice < 0 || item.ActualPrice < 0 {
+		return errors.New("item price is negative")
+	}
+
+	unit, err := model.NormalizeUnit(item.Unit)
+	if err != nil {
+		return err
+	}
+	item.Unit = unit
+
+	return nil
+}
+
+// normalizeUpdate validates the changed fields of an item and brings its unit and category to their canonical names.
+func normalizeUpdate(input *model.UpdateItemInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.Unit != nil {
+		unit, err := model.NormalizeUnit(*input.Unit)
+		if err != nil {
+			return err
+		}
+		input.Unit = &unit
+	}
+	if input.Category != nil {
+		category, err := model.NormalizeCategory(*input.Category)
+		if err != nil {
+			return err
+		}
+		input.Category = &category
+	}
+
+	return nil
+}
+
+func sameProduct(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
 }
 
-func (s *ShoppingItemService) Update(userId, itemId int, input model.UpdateItemInput) error {
-	return s.repo.Update(userId, itemId, input)
+// classify remembers the category set on a new item or, when it has none, takes the category
+// the user has given to the product before or the one from the built-in dictionary.
+func (s *ShoppingItemService) classify(userId int, item *model.ShoppingItem) error {
+	key := categoryKey(item.Title)
+	if item.Category != "" {
+		category, err := model.NormalizeCategory(item.Category)
+		if err != nil {
+			return err
+		}
+		item.Category = category
+		return s.categoryRepo.Remember(userId, key, category)
+	}
+
+	category, err := s.categoryRepo.Lookup(userId, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		item.Category = seedCategory(item.Title)
+		return nil
+	}
+	item.Category = category
+
+	return err
 }
@@ -1,37 +1,157 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"github.com/elecshen/shopping_list/internal/model"
 	"github.com/elecshen/shopping_list/internal/repository"
 )
 
 type ShoppingListService struct {
-	repo repository.List
+	repo      repository.List
+	storeRepo repository.Store
 }
 
-func NewShoppingListService(repo repository.List) *ShoppingListService {
-	return &ShoppingListService{repo: repo}
+func NewShoppingListService(repo repository.List, storeRepo repository.Store) *ShoppingListService {
+	return &ShoppingListService{repo: repo, storeRepo: storeRepo}
 }
 
 func (s *ShoppingListService) Create(userId int, list model.ShoppingList) (int, error) {
+	if list.Budget < 0 {
+		return 0, errors.New("list budget must not be negative")
+	}
+	currency, err := model.NormalizeCurrency(list.Currency)
+	if err != nil {
+		return 0, err
+	}
+	list.Currency = currency
+
 	return s.repo.Create(userId, list)
 }
 
-func (s *ShoppingListService) GetAll(userId int) ([]model.ShoppingList, error) {
-	return s.repo.GetAll(userId)
+func (s *ShoppingListService) GetAll(userId int, archived *bool) ([]model.ShoppingList, error) {
+	return s.repo.GetAll(userId, archived)
 }
 
+// GetById returns the list with the totals of its item prices.
 func (s *ShoppingListService) GetById(userId, listId int) (model.ShoppingList, error) {
-	return s.repo.GetById(userId, listId)
+	list, err := s.repo.GetById(userId, listId)
+	if err != nil {
+		return list, err
+	}
+
+	totals, err := s.repo.GetTotals(listId)
+	if err != nil {
+		return list, err
+	}
+	totals.ApplyBudget(list.Budget)
+	list.Totals = &totals
+
+	return list, nil
+}
+
+// BudgetWarning returns a warning when the expected cost of the list exceeds its budget.
+func (s *ShoppingListService) BudgetWarning(userId, listId int) (string, error) {
+	return budgetWarning(s.repo, userId, listId)
+}
+
+func budgetWarning(repo repository.List, userId, listId int) (string, error) {
+	list, err := repo.GetById(userId, listId)
+	if err != nil || list.Budget == 0 {
+		return "", err
+	}
+
+	totals, err := repo.GetTotals(listId)
+	if err != nil {
+		return "", err
+	}
+	totals.ApplyBudget(list.Budget)
+	if !totals.OverBudget {
+		return "", nil
+	}
+
+	return fmt.Sprintf("expected cost %s exceeds the budget %s", model.FormatPrice(totals.Spent+totals.Pending, list.Currency),
+		model.FormatPrice(list.Budget, list.Currency)), nil
+}
+
+func (s *ShoppingListService) Update(userId, listId int, input model.UpdateListInput, version int) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.Currency != nil {
+		currency, _ := model.NormalizeCurrency(*input.Currency)
+		input.Currency = &currency
+		if err := s.checkCurrencyChange(userId, listId, currency); err != nil {
+			return err
+		}
+	}
+	if input.StoreId != nil && *input.StoreId != 0 {
+		if _, err := s.storeRepo.GetById(userId, *input.StoreId); err != nil {
+			// store does not exist or does not belong to user
+			return err
+		}
+	}
+	return s.repo.Update(userId, listId, input, version)
+}
+
+// checkCurrencyChange allows changing the currency of a list only while none of its items has a price,
+// prices are not converted.
+func (s *ShoppingListService) checkCurrencyChange(userId, listId int, currency string) error {
+	list, err := s.repo.GetById(userId, listId)
+	if err != nil || list.Currency == currency {
+		return err
+	}
+
+	totals, err := s.repo.GetTotals(listId)
+	if err != nil {
+		return err
+	}
+	if totals.Estimated > 0 {
+		return model.ErrCurrencyMismatch
+	}
+
+	return nil
+}
+
+func (s *ShoppingListService) SetArchived(userId, listId int, archived bool) error {
+	if _, err := s.repo.GetById(userId, listId); err != nil {
+		return err
+	}
+	return s.repo.Update(userId, listId, model.UpdateListInput{Archived: &archived}, 0)
 }
 
-func (s *ShoppingListService) Update(userId, listId int, input model.UpdateListInput) error {
+func (s *ShoppingListService) Reorder(userId int, ids []int) error {
+	if err := (model.ListOrderInput{Ids: ids}).Validate(); err != nil {
+		return err
+	}
+	return s.repo.Reorder(userId, ids)
+}
+
+func (s *ShoppingListService) Duplicate(userId, listId int, input model.DuplicateListInput) (int, error) {
+	if _, err := s.repo.GetById(userId, listId); err != nil {
+		// list does not exist or does not belong to user
+		return 0, err
+	}
+	return s.repo.Duplicate(userId, listId, input.Title, input.UncheckedOnly)
+}
+
+func (s *ShoppingListService) Merge(userId int, input model.MergeListsInput) error {
 	if err := input.Validate(); err != nil {
 		return err
 	}
-	return s.repo.Update(userId, listId, input)
+	return s.repo.Merge(userId, input.TargetId, input.Ids)
+}
+
+func (s *ShoppingListService) Split(userId, listId int, input model.SplitListInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+	if _, err := s.repo.GetById(userId, listId); err != nil {
+		return 0, err
+	}
+	return s.repo.Split(userId, listId, input.Title, input.ItemIds)
 }
 
-func (s *ShoppingListService) Delete(userId, listId int) error {
-	return s.repo.Delete(userId, listId)
+func (s *ShoppingListService) Delete(userId, listId int, version int) error {
+	return s.repo.Delete(userId, listId, version)
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+)
+
+type SnapshotService struct {
+	repo     repository.Snapshot
+	listRepo repository.List
+	itemRepo repository.Item
+}
+
+func NewSnapshotService(repo repository.Snapshot, listRepo repository.List, itemRepo repository.Item) *SnapshotService {
+	return &SnapshotService{repo: repo, listRepo: listRepo, itemRepo: itemRepo}
+}
+
+func (s *SnapshotService) Create(userId, listId int) (int, error) {
+	return s.repo.Create(userId, listId)
+}
+
+func (s *SnapshotService) GetAll(userId, listId int) ([]model.ListSnapshot, error) {
+	return s.repo.GetAll(userId, listId)
+}
+
+func (s *SnapshotService) GetById(userId, snapshotId int) (model.ListSnapshot, error) {
+	return s.repo.GetById(userId, snapshotId)
+}
+
+// Diff compares snapshot fromId with snapshot toId, or with the current state of the list when toId is 0.
+func (s *SnapshotService) Diff(userId, fromId, toId int) (model.ListDiff, error) {
+	from, err := s.repo.GetById(userId, fromId)
+	if err != nil {
+		return model.ListDiff{}, err
+	}
+
+	var to model.ListSnapshot
+	if toId != 0 {
+		to, err = s.repo.GetById(userId, toId)
+		if err != nil {
+			return model.ListDiff{}, err
+		}
+		if to.ListId != from.ListId {
+			return model.ListDiff{}, errors.New("snapshots belong to different lists")
+		}
+	} else {
+		to, err = s.currentState(userId, from.ListId)
+		if err != nil {
+			return model.ListDiff{}, err
+		}
+	}
+
+	return diffSnapshots(from, to), nil
+}
+
+func (s *SnapshotService) Restore(userId, snapshotId int) (model.SnapshotRestore, error) {
+	return s.repo.Restore(userId, snapshotId)
+}
+
+func (s *SnapshotService) currentState(userId, listId int) (model.ListSnapshot, error) {
+	list, err := s.listRepo.GetById(userId, listId)
+	if err != nil {
+		return model.ListSnapshot{}, err
+	}
+
+	items, err := s.itemRepo.GetAll(userId, listId)
+	if err != nil {
+		return model.ListSnapshot{}, err
+	}
+
+	return model.ListSnapshot{ListId: list.Id, Title: list.Title, Description: list.Description, Items: items}, nil
+}
+
+func diffSnapshots(from, to model.ListSnapshot) model.ListDiff {
+	diff := model.ListDiff{
+		From:       from.Id,
+		To:         to.Id,
+		ListFields: make([]string, 0),
+		Added:      make([]model.ShoppingItem, 0),
+		Removed:    make([]model.ShoppingItem, 0),
+		Changed:    make([]model.ItemChange, 0),
+	}
+
+	if from.Title != to.Title {
+		diff.ListFields = append(diff.ListFields, "title")
+	}
+	if from.Description != to.Description {
+		diff.ListFields = append(diff.ListFields, "description")
+	}
+
+	before := make(map[int]model.ShoppingItem, len(from.Items))
+	for _, item := range from.Items {
+		before[item.Id] = item
+	}
+
+	for _, item := range to.Items {
+		old, ok := before[item.Id]
+		if !ok {
+			diff.Added = append(diff.Added, item)
+			continue
+		}
+		delete(before, item.Id)
+
+		if fields := changedItemFields(old, item); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, model.ItemChange{Id: item.Id, Fields: fields, Before: old, After: item})
+		}
+	}
+
+	for _, item := range from.Items {
+		if _, ok := before[item.Id]; ok {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+
+	return diff
+}
+
+func changedItemFields(a, b model.ShoppingItem) []string {
+	fields := make([]string, 0)
+	if a.Title != b.Title {
+		fields = append(fields, "title")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "description")
+	}
+	if a.Checked != b.Checked {
+		fields = append(fields, "checked")
+	}
+	if a.Quantity != b.Quantity {
+		fields = append(fields, "quantity")
+	}
+	if a.Unit != b.Unit {
+		fields = append(fields, "unit")
+	}
+	if a.Category != b.Category {
+		fields = append(fields, "category")
+	}
+	if a.EstimatedPrice != b.EstimatedPrice {
+		fields = append(fields, "estimatedPrice")
+	}
+	if a.ActualPrice != b.ActualPrice {
+		fields = append(fields, "actualPrice")
+	}
+
+	return fields
+}
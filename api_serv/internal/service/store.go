@@ -0,0 +1,90 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"math"
+	"sort"
+)
+
+type StoreService struct {
+	repo repository.Store
+}
+
+func NewStoreService(repo repository.Store) *StoreService {
+	return &StoreService{repo: repo}
+}
+
+func (s *StoreService) Create(userId int, store model.Store) (int, error) {
+	aisles, err := model.NormalizeAisles(store.Aisles)
+	if err != nil {
+		return 0, err
+	}
+	store.Aisles = aisles
+
+	return s.repo.Create(userId, store)
+}
+
+func (s *StoreService) GetAll(userId int) ([]model.Store, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *StoreService) GetById(userId, storeId int) (model.Store, error) {
+	return s.repo.GetById(userId, storeId)
+}
+
+func (s *StoreService) Update(userId, storeId int, input model.UpdateStoreInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.Aisles != nil {
+		aisles, err := model.NormalizeAisles(*input.Aisles)
+		if err != nil {
+			return err
+		}
+		input.Aisles = &aisles
+	}
+
+	return s.repo.Update(userId, storeId, input)
+}
+
+func (s *StoreService) Delete(userId, storeId int) error {
+	return s.repo.Delete(userId, storeId)
+}
+
+// sortByStore sorts the items in the walking order of the store: by the aisle of their
+// category, categories without an aisle last, then by the position the product is usually
+// checked off at, products never checked off in the store last, then by title.
+func sortByStore(items []model.ShoppingItem, store model.Store, ranks map[string]float64) {
+	aisles := make(map[string]int, len(store.Aisles))
+	for i, aisle := range store.Aisles {
+		aisles[aisle] = i
+	}
+
+	aisle := func(item model.ShoppingItem) int {
+		category := item.Category
+		if category == "" {
+			category = model.CategoryOther
+		}
+		if i, ok := aisles[category]; ok {
+			return i
+		}
+		return len(aisles)
+	}
+	rank := func(item model.ShoppingItem) float64 {
+		if r, ok := ranks[categoryKey(item.Title)]; ok {
+			return r
+		}
+		return math.Inf(1)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if ai, aj := aisle(items[i]), aisle(items[j]); ai != aj {
+			return ai < aj
+		}
+		if ri, rj := rank(items[i]), rank(items[j]); ri != rj {
+			return ri < rj
+		}
+		return categoryKey(items[i].Title) < categoryKey(items[j].Title)
+	})
+}
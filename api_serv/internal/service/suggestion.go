@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// suggestionHistory is how far back purchases are analyzed
+	suggestionHistory = 365 * 24 * time.Hour
+	// suggestionMinIntervals is the number of intervals between purchase days a prediction needs
+	suggestionMinIntervals = 2
+	// suggestionDueRatio is the part of the interval after which a product is due
+	suggestionDueRatio = 0.8
+	// suggestionMinConfidence is the confidence below which products are not suggested
+	suggestionMinConfidence = 0.2
+)
+
+type SuggestionService struct {
+	repo     repository.Purchase
+	listRepo repository.List
+	itemRepo repository.Item
+	items    *ShoppingItemService
+}
+
+func NewSuggestionService(repo repository.Purchase, listRepo repository.List, itemRepo repository.Item,
+	items *ShoppingItemService) *SuggestionService {
+	return &SuggestionService{repo: repo, listRepo: listRepo, itemRepo: itemRepo, items: items}
+}
+
+// GetAll returns the products that are probably due to be bought again and are not on the list,
+// the most confident first.
+func (s *SuggestionService) GetAll(userId, listId int) ([]model.Suggestion, error) {
+	if _, err := s.listRepo.GetById(userId, listId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	history, err := s.repo.GetHistory(userId, now.Add(-suggestionHistory))
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.itemRepo.GetAll(userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]bool, len(items))
+	for _, item := range items {
+		if !item.Checked {
+			listed[categoryKey(item.Title)] = true
+		}
+	}
+
+	suggestions := make([]model.Suggestion, 0)
+	for _, product := range history {
+		if listed[categoryKey(product.Title)] {
+			continue
+		}
+		if suggestion, ok := suggest(product, now); ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+
+	return suggestions, nil
+}
+
+// Add adds the suggested product to the list with the quantity and unit of its latest purchase.
+func (s *SuggestionService) Add(userId, listId int, input model.AddSuggestionInput) (int, error) {
+	suggestions, err := s.GetAll(userId, listId)
+	if err != nil {
+		return 0, err
+	}
+
+	key := categoryKey(input.Title)
+	for _, suggestion := range suggestions {
+		if categoryKey(suggestion.Title) == key {
+			item := model.ShoppingItem{Title: suggestion.Title, Quantity: suggestion.Quantity, Unit: suggestion.Unit,
+				Category: suggestion.Category}
+			return s.items.Create(userId, listId, item)
+		}
+	}
+
+	return 0, errors.New("product is not suggested")
+}
+
+// suggest predicts whether the product is due at now.
+func suggest(product model.PurchaseHistory, now time.Time) (model.Suggestion, bool) {
+	days := make([]time.Time, 0, len(product.Days))
+	for _, day := range product.Days {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return model.Suggestion{}, false
+		}
+		days = append(days, date)
+	}
+
+	interval, confidence, ok := predictRepurchase(days, now)
+	if !ok || confidence < suggestionMinConfidence {
+		return model.Suggestion{}, false
+	}
+
+	return model.Suggestion{
+		Title:        product.Title,
+		Category:     product.Category,
+		Quantity:     product.Quantity,
+		Unit:         product.Unit,
+		Interval:     math.Round(interval*10) / 10,
+		LastPurchase: product.Last,
+		Confidence:   math.Round(confidence*100) / 100,
+	}, true
+}
+
+// predictRepurchase estimates the repurchase interval in days as the median of the intervals
+// between the purchase days, sorted from the oldest, and reports whether the product is due at now.
+// The confidence multiplies three scores from 0 to 1: the number of intervals, their regularity
+// measured by the median absolute deviation, and how due the product is, which grows up to the
+// interval and fades after twice the interval when the product is probably not bought anymore.
+func predictRepurchase(days []time.Time, now time.Time) (float64, float64, bool) {
+	if len(days) < suggestionMinIntervals+1 {
+		return 0, 0, false
+	}
+
+	intervals := make([]float64, 0, len(days)-1)
+	for i := 1; i < len(days); i++ {
+		intervals = append(intervals, days[i].Sub(days[i-1]).Hours()/24)
+	}
+	interval := median(intervals)
+	if interval <= 0 {
+		return 0, 0, false
+	}
+
+	elapsed := now.Sub(days[len(days)-1]).Hours() / 24
+	ratio := elapsed / interval
+	if ratio < suggestionDueRatio {
+		return interval, 0, false
+	}
+
+	deviations := make([]float64, len(intervals))
+	for i, v := range intervals {
+		deviations[i] = math.Abs(v - interval)
+	}
+
+	samples := float64(len(intervals)) / float64(len(intervals)+3)
+	regularity := math.Max(0, 1-median(deviations)/interval)
+	due := math.Min(1, ratio)
+	if ratio > 2 {
+		due = math.Max(0, 1-(ratio-2)/2)
+	}
+
+	return interval, samples * regularity * due, true
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
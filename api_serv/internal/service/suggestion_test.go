@@ -0,0 +1,162 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"math"
+	"testing"
+	"time"
+)
+
+// 2025-03-03 is the first purchase day of the tests
+var firstPurchase = time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+// purchaseDays returns the days that many days after the first purchase.
+func purchaseDays(offsets ...int) []time.Time {
+	days := make([]time.Time, 0, len(offsets))
+	for _, offset := range offsets {
+		days = append(days, firstPurchase.AddDate(0, 0, offset))
+	}
+
+	return days
+}
+
+func TestPredictRepurchase(t *testing.T) {
+	tests := []struct {
+		name       string
+		days       []time.Time
+		now        int
+		interval   float64
+		confidence float64
+		ok         bool
+	}{
+		{
+			name:       "regular intervals",
+			days:       purchaseDays(0, 7, 14, 21),
+			now:        28,
+			interval:   7,
+			confidence: 0.5,
+			ok:         true,
+		},
+		{
+			name:       "irregular intervals",
+			days:       purchaseDays(0, 5, 14, 20),
+			now:        26,
+			interval:   6,
+			confidence: 0.5 * (1 - 1.0/6),
+			ok:         true,
+		},
+		{
+			name:       "median of an even count",
+			days:       purchaseDays(0, 4, 10),
+			now:        15,
+			interval:   5,
+			confidence: 0.4 * 0.8,
+			ok:         true,
+		},
+		{
+			name: "fewer than 3 purchase days",
+			days: purchaseDays(0, 7),
+			now:  14,
+		},
+		{
+			name: "purchases on the same day",
+			days: purchaseDays(0, 0, 0),
+			now:  1,
+		},
+		{
+			name:     "not yet due",
+			days:     purchaseDays(0, 7, 14),
+			now:      19,
+			interval: 7,
+		},
+		{
+			name:       "due from 0.8 of the interval",
+			days:       purchaseDays(0, 10, 20),
+			now:        28,
+			interval:   10,
+			confidence: 0.4 * 0.8,
+			ok:         true,
+		},
+		{
+			name:       "overdue twice the interval",
+			days:       purchaseDays(0, 7, 14, 21),
+			now:        35,
+			interval:   7,
+			confidence: 0.5,
+			ok:         true,
+		},
+		{
+			name:       "fading after twice the interval",
+			days:       purchaseDays(0, 7, 14, 21),
+			now:        42,
+			interval:   7,
+			confidence: 0.25,
+			ok:         true,
+		},
+		{
+			name:     "faded at four times the interval",
+			days:     purchaseDays(0, 7, 14, 21),
+			now:      49,
+			interval: 7,
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval, confidence, ok := predictRepurchase(tt.days, firstPurchase.AddDate(0, 0, tt.now))
+			if ok != tt.ok || interval != tt.interval || math.Abs(confidence-tt.confidence) > 1e-9 {
+				t.Fatalf("got %v, %v, %v, want %v, %v, %v", interval, confidence, ok, tt.interval, tt.confidence, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	last := firstPurchase.AddDate(0, 0, 20).Add(18 * time.Hour)
+	product := model.PurchaseHistory{Title: "Milk", Category: "dairy", Quantity: 2, Unit: "l",
+		Days: []string{"2025-03-03", "2025-03-08", "2025-03-17", "2025-03-23"}, Last: last}
+
+	tests := []struct {
+		name    string
+		days    []string
+		now     int
+		want    model.Suggestion
+		suggest bool
+	}{
+		{
+			name: "due with a rounded confidence",
+			days: product.Days,
+			now:  26,
+			want: model.Suggestion{Title: "Milk", Category: "dairy", Quantity: 2, Unit: "l", Interval: 6,
+				LastPurchase: last, Confidence: 0.42},
+			suggest: true,
+		},
+		{
+			name: "not yet due",
+			days: product.Days,
+			now:  22,
+		},
+		{
+			name: "confidence below the minimum",
+			days: product.Days,
+			now:  40,
+		},
+		{
+			name: "invalid day",
+			days: []string{"2025-03-03", "2025-03-08", "tomorrow"},
+			now:  26,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := product
+			p.Days = tt.days
+			got, ok := suggest(p, firstPurchase.AddDate(0, 0, tt.now))
+			if ok != tt.suggest || got != tt.want {
+				t.Fatalf("got %+v, %v, want %+v, %v", got, ok, tt.want, tt.suggest)
+			}
+		})
+	}
+}
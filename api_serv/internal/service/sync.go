@@ -0,0 +1,404 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+)
+
+// mergeAttempts limits retries of a merge that keeps losing the race to concurrent writers.
+const mergeAttempts = 5
+
+type SyncService struct {
+	repo      repository.Sync
+	listRepo  repository.List
+	itemRepo  repository.Item
+	stateRepo repository.ItemState
+	clock     *HybridClock
+}
+
+func NewSyncService(repo repository.Sync, listRepo repository.List, itemRepo repository.Item,
+	stateRepo repository.ItemState, clock *HybridClock) *SyncService {
+	return &SyncService{repo: repo, listRepo: listRepo, itemRepo: itemRepo, stateRepo: stateRepo, clock: clock}
+}
+
+func (s *SyncService) Pull(userId, since int) (model.SyncChanges, error) {
+	if since < 0 {
+		return model.SyncChanges{}, errors.New("invalid cursor")
+	}
+	return s.repo.GetChanges(userId, since)
+}
+
+// Push applies client mutations in order. A failed mutation does not stop the batch,
+// its outcome is reported in the corresponding result.
+func (s *SyncService) Push(userId int, mutations []model.SyncMutation) []model.SyncResult {
+	results := make([]model.SyncResult, 0, len(mutations))
+	for _, m := range mutations {
+		results = append(results, s.apply(userId, m))
+	}
+
+	return results
+}
+
+func (s *SyncService) apply(userId int, m model.SyncMutation) model.SyncResult {
+	var result model.SyncResult
+	var err error
+
+	if m.Clock != nil {
+		if _, err = s.clock.Update(*m.Clock); err != nil {
+			return model.SyncResult{ClientId: m.ClientId, Status: model.SyncStatusError, Error: err.Error()}
+		}
+	}
+
+	switch m.Op {
+	case model.SyncOpCreateList:
+		result, err = s.createList(userId, m)
+	case model.SyncOpUpdateList, model.SyncOpDeleteList:
+		result, err = s.changeList(userId, m)
+	case model.SyncOpCreateItem:
+		result, err = s.createItem(userId, m)
+	case model.SyncOpUpdateItem:
+		if m.Clock != nil {
+			result, err = s.mergeItem(userId, m)
+		} else {
+			result, err = s.changeItem(userId, m)
+		}
+	case model.SyncOpDeleteItem:
+		if m.Clock != nil || len(m.Tags) > 0 {
+			result, err = s.removeItem(userId, m)
+		} else {
+			result, err = s.changeItem(userId, m)
+		}
+	default:
+		err = errors.New("unknown operation " + m.Op)
+	}
+
+	result.ClientId = m.ClientId
+	if err != nil {
+		result.Status = model.SyncStatusError
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// createdId returns the ID of the object already created for the client ID, or 0 if there is none.
+func (s *SyncService) createdId(userId int, clientId, entity string) (int, error) {
+	savedEntity, id, err := s.repo.GetClientId(userId, clientId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return mappedId(entity, savedEntity, id)
+}
+
+// mappedId returns the ID a client ID is mapped to if the mapping is for an object of the entity.
+func mappedId(entity, savedEntity string, id int) (int, error) {
+	if savedEntity != entity {
+		return 0, errors.New("client id is already used by another " + savedEntity)
+	}
+
+	return id, nil
+}
+
+func (s *SyncService) createList(userId int, m model.SyncMutation) (model.SyncResult, error) {
+	id, err := s.createdId(userId, m.ClientId, model.SyncEntityList)
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	if id == 0 {
+		if m.Title == nil {
+			return model.SyncResult{}, errors.New("list title is required")
+		}
+
+		list := model.ShoppingList{Title: *m.Title}
+		if m.Description != nil {
+			list.Description = *m.Description
+		}
+		entity, mapped, err := s.repo.CreateList(userId, m.ClientId, list)
+		if err != nil {
+			return model.SyncResult{}, err
+		}
+		if id, err = mappedId(model.SyncEntityList, entity, mapped); err != nil {
+			return model.SyncResult{}, err
+		}
+	}
+
+	list, err := s.listRepo.GetById(userId, id)
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	return model.SyncResult{Status: model.SyncStatusApplied, Id: id, Version: list.Version}, nil
+}
+
+func (s *SyncService) changeList(userId int, m model.SyncMutation) (model.SyncResult, error) {
+	if _, err := s.listRepo.GetById(userId, m.Id); errors.Is(err, sql.ErrNoRows) {
+		return model.SyncResult{Status: model.SyncStatusConflict, Id: m.Id, Error: "list was deleted"}, nil
+	} else if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	var err error
+	if m.Op == model.SyncOpDeleteList {
+		err = s.listRepo.Delete(userId, m.Id, m.Version)
+	} else {
+		input := model.UpdateListInput{Title: m.Title, Description: m.Description}
+		if err = input.Validate(); err != nil {
+			return model.SyncResult{}, err
+		}
+		err = s.listRepo.Update(userId, m.Id, input, m.Version)
+	}
+	if err != nil && !errors.Is(err, model.ErrVersionMismatch) {
+		return model.SyncResult{}, err
+	}
+
+	result := model.SyncResult{Status: model.SyncStatusApplied, Id: m.Id}
+	if err != nil {
+		result.Status = model.SyncStatusConflict
+		result.Error = err.Error()
+	}
+	if err != nil || m.Op == model.SyncOpUpdateList {
+		list, err := s.listRepo.GetById(userId, m.Id)
+		if err != nil {
+			return model.SyncResult{}, err
+		}
+		result.Version = list.Version
+		if result.Status == model.SyncStatusConflict {
+			result.CurrentList = &list
+		}
+	}
+
+	return result, nil
+}
+
+// createItem adds an item to a list. With Id set it re-adds an item the client already
+// knows, recreating it under the same ID if it was deleted concurrently. An ID the server has
+// not deleted is never taken from the client: such an item is created under a new ID returned
+// in the result.
+func (s *SyncService) createItem(userId int, m model.SyncMutation) (model.SyncResult, error) {
+	id, err := s.createdId(userId, m.ClientId, model.SyncEntityItem)
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	if id == 0 {
+		listId := m.ListId
+		if m.ListClientId != "" {
+			if listId, err = s.createdId(userId, m.ListClientId, model.SyncEntityList); err != nil {
+				return model.SyncResult{}, err
+			}
+			if listId == 0 {
+				return model.SyncResult{}, errors.New("unknown list client id " + m.ListClientId)
+			}
+		}
+		if _, err = s.listRepo.GetById(userId, listId); errors.Is(err, sql.ErrNoRows) {
+			return model.SyncResult{Status: model.SyncStatusConflict, Error: "list was deleted"}, nil
+		} else if err != nil {
+			return model.SyncResult{}, err
+		}
+
+		if m.Title == nil {
+			return model.SyncResult{}, errors.New("item title is required")
+		}
+		input := m.ItemInput()
+		if err = normalizeUpdate(&input); err != nil {
+			return model.SyncResult{}, err
+		}
+
+		item := model.ShoppingItem{Id: m.Id, Title: *input.Title, Quantity: 1, Unit: model.DefaultUnit}
+		if input.Description != nil {
+			item.Description = *input.Description
+		}
+		if input.Checked != nil {
+			item.Checked = *input.Checked
+		}
+		if input.Quantity != nil {
+			item.Quantity = *input.Quantity
+		}
+		if input.Unit != nil {
+			item.Unit = *input.Unit
+		}
+		if input.Category != nil {
+			item.Category = *input.Category
+		}
+		if input.EstimatedPrice != nil {
+			item.EstimatedPrice = *input.EstimatedPrice
+		}
+		if input.ActualPrice != nil {
+			item.ActualPrice = *input.ActualPrice
+		}
+
+		if id, err = s.addItem(userId, listId, item, input, m); err != nil {
+			return model.SyncResult{}, err
+		}
+	}
+
+	item, err := s.itemRepo.GetById(userId, id)
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	return model.SyncResult{Status: model.SyncStatusApplied, Id: id, Version: item.Version}, nil
+}
+
+// addItem merges the fields of input into the existing item with its ID or creates the item, and returns its ID.
+func (s *SyncService) addItem(userId, listId int, item model.ShoppingItem, input model.UpdateItemInput,
+	m model.SyncMutation) (int, error) {
+	ts := s.clock.Now()
+	if m.Clock != nil {
+		ts = *m.Clock
+	}
+
+	if item.Id != 0 {
+		if _, err := s.stateRepo.Get(userId, item.Id); err == nil {
+			if _, err = s.mergeFields(userId, item.Id, input, ts); err != nil {
+				return 0, err
+			}
+			if err = s.stateRepo.AddTag(listId, item.Id, m.ClientId); err != nil {
+				return 0, err
+			}
+			return item.Id, s.repo.SaveClientId(userId, m.ClientId, model.SyncEntityItem, item.Id)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	entity, id, err := s.repo.CreateItem(userId, m.ClientId, listId, item, stampItemFields(input, ts))
+	if err != nil {
+		return 0, err
+	}
+
+	return mappedId(model.SyncEntityItem, entity, id)
+}
+
+// mergeItem applies an update field by field, keeping for every field the write with the newest clock.
+func (s *SyncService) mergeItem(userId int, m model.SyncMutation) (model.SyncResult, error) {
+	input := m.ItemInput()
+	if err := normalizeUpdate(&input); err != nil {
+		return model.SyncResult{}, err
+	}
+
+	version, err := s.mergeFields(userId, m.Id, input, *m.Clock)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SyncResult{Status: model.SyncStatusConflict, Id: m.Id, Error: "item was deleted"}, nil
+	}
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	return model.SyncResult{Status: model.SyncStatusApplied, Id: m.Id, Version: version}, nil
+}
+
+// mergeFields writes the fields of input that are newer than the stored ones and returns the item version.
+func (s *SyncService) mergeFields(userId, itemId int, input model.UpdateItemInput, ts model.Timestamp) (int, error) {
+	for attempt := 0; attempt < mergeAttempts; attempt++ {
+		state, err := s.stateRepo.Get(userId, itemId)
+		if err != nil {
+			return 0, err
+		}
+
+		merged := mergeItemFields(state.Clocks, input, ts)
+		if merged.Validate() != nil {
+			// every field already holds a newer write
+			return state.Version, nil
+		}
+
+		err = s.itemRepo.Update(userId, itemId, merged, state.Version)
+		if errors.Is(err, model.ErrVersionMismatch) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return state.Version + 1, nil
+	}
+
+	return 0, errors.New("item is being changed concurrently, try again")
+}
+
+// removeItem removes the membership tags the client has observed, or all current ones if it sent none.
+// The item is deleted only if no add tag is left, so an add the client has not seen keeps it in the list.
+func (s *SyncService) removeItem(userId int, m model.SyncMutation) (model.SyncResult, error) {
+	state, err := s.stateRepo.Get(userId, m.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SyncResult{Status: model.SyncStatusApplied, Id: m.Id}, nil
+	}
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	tags, err := s.stateRepo.GetTags(userId, m.Id)
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	set := newORSet(tags)
+	observed := m.Tags
+	if len(observed) == 0 {
+		observed = set.live()
+	}
+	set.remove(observed)
+
+	if len(observed) > 0 {
+		if err = s.stateRepo.RemoveTags(state.ListId, m.Id, observed); err != nil {
+			return model.SyncResult{}, err
+		}
+	}
+
+	if set.contains() {
+		return model.SyncResult{Status: model.SyncStatusConflict, Id: m.Id, Version: state.Version,
+			Error: "item was added again concurrently", CurrentItem: &state.ShoppingItem}, nil
+	}
+	if err = s.itemRepo.Delete(userId, m.Id, 0); err != nil {
+		return model.SyncResult{}, err
+	}
+
+	return model.SyncResult{Status: model.SyncStatusApplied, Id: m.Id}, nil
+}
+
+func (s *SyncService) changeItem(userId int, m model.SyncMutation) (model.SyncResult, error) {
+	if _, err := s.itemRepo.GetById(userId, m.Id); errors.Is(err, sql.ErrNoRows) {
+		return model.SyncResult{Status: model.SyncStatusConflict, Id: m.Id, Error: "item was deleted"}, nil
+	} else if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	var err error
+	if m.Op == model.SyncOpDeleteItem {
+		err = s.itemRepo.Delete(userId, m.Id, m.Version)
+	} else {
+		input := m.ItemInput()
+		if err = normalizeUpdate(&input); err != nil {
+			return model.SyncResult{}, err
+		}
+		err = s.itemRepo.Update(userId, m.Id, input, m.Version)
+	}
+	if err != nil && !errors.Is(err, model.ErrVersionMismatch) {
+		return model.SyncResult{}, err
+	}
+
+	result := model.SyncResult{Status: model.SyncStatusApplied, Id: m.Id}
+	if err != nil {
+		result.Status = model.SyncStatusConflict
+		result.Error = err.Error()
+	}
+	if err != nil || m.Op == model.SyncOpUpdateItem {
+		item, err := s.itemRepo.GetById(userId, m.Id)
+		if err != nil {
+			return model.SyncResult{}, err
+		}
+		result.Version = item.Version
+		if result.Status == model.SyncStatusConflict {
+			result.CurrentItem = &item
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+)
+
+type TemplateService struct {
+	repo     repository.Template
+	listRepo repository.List
+	itemRepo repository.Item
+}
+
+func NewTemplateService(repo repository.Template, listRepo repository.List, itemRepo repository.Item) *TemplateService {
+	return &TemplateService{repo: repo, listRepo: listRepo, itemRepo: itemRepo}
+}
+
+func (s *TemplateService) Create(userId int, input model.CreateTemplateInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	template := model.ListTemplate{Title: input.Title, Description: input.Description, Items: input.Items}
+	if input.ListId != 0 {
+		list, err := s.listRepo.GetById(userId, input.ListId)
+		if err != nil {
+			return 0, err
+		}
+		items, err := s.itemRepo.GetAll(userId, input.ListId)
+		if err != nil {
+			return 0, err
+		}
+
+		if template.Title == "" {
+			template.Title = list.Title
+		}
+		for _, item := range items {
+			template.Items = append(template.Items, model.TemplateItem{Title: item.Title, Description: item.Description,
+				Quantity: item.Quantity, Unit: item.Unit, Category: item.Category})
+		}
+	}
+	if err := normalizeTemplateItems(template.Items); err != nil {
+		return 0, err
+	}
+
+	return s.repo.Create(userId, template)
+}
+
+func (s *TemplateService) GetAll(userId int) ([]model.ListTemplate, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *TemplateService) GetById(userId, templateId int) (model.ListTemplate, error) {
+	return s.repo.GetById(userId, templateId)
+}
+
+func (s *TemplateService) Update(userId, templateId int, input model.UpdateTemplateInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.Items != nil {
+		if err := normalizeTemplateItems(*input.Items); err != nil {
+			return err
+		}
+	}
+	return s.repo.Update(userId, templateId, input)
+}
+
+func (s *TemplateService) Delete(userId, templateId int) error {
+	return s.repo.Delete(userId, templateId)
+}
+
+func (s *TemplateService) Instantiate(userId, templateId int, input model.InstantiateTemplateInput) (int, error) {
+	return s.repo.Instantiate(userId, templateId, input.Title)
+}
+
+// normalizeTemplateItems sets the quantity of items created without one to a single unit
+// and normalizes their units and categories like the ones of list items.
+func normalizeTemplateItems(items []model.TemplateItem) error {
+	for i := range items {
+		if items[i].Quantity == 0 {
+			items[i].Quantity = 1
+		}
+
+		unit, err := model.NormalizeUnit(items[i].Unit)
+		if err != nil {
+			return err
+		}
+		category, err := model.NormalizeCategory(items[i].Category)
+		if err != nil {
+			return err
+		}
+		items[i].Unit, items[i].Category = unit, category
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"github.com/elecshen/shopping_list/internal/model"
+	"github.com/elecshen/shopping_list/internal/repository"
+	"strings"
+)
+
+type TripService struct {
+	repo      repository.Trip
+	listRepo  repository.List
+	storeRepo repository.Store
+	clock     *HybridClock
+}
+
+func NewTripService(repo repository.Trip, listRepo repository.List, storeRepo repository.Store, clock *HybridClock) *TripService {
+	return &TripService{repo: repo, listRepo: listRepo, storeRepo: storeRepo, clock: clock}
+}
+
+func (s *TripService) Start(userId, listId int, input model.StartTripInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+	if _, err := s.listRepo.GetById(userId, listId); err != nil {
+		// list does not exist or does not belong to user
+		return 0, err
+	}
+	if input.StoreId != nil {
+		if _, err := s.storeRepo.GetById(userId, *input.StoreId); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.repo.Start(userId, listId, input.StoreId, strings.TrimSpace(input.Shopper))
+}
+
+func (s *TripService) GetAll(userId int) ([]model.Trip, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *TripService) GetById(userId, tripId int) (model.Trip, error) {
+	return s.repo.GetById(userId, tripId)
+}
+
+func (s *TripService) Finish(userId, tripId int, input model.FinishTripInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	unchecked := false
+	clocks := stampItemFields(model.UpdateItemInput{Checked: &unchecked}, s.clock.Now())
+
+	return s.repo.Finish(userId, tripId, input.Purchased, clocks)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// SignUp creates a user and returns its ID.
+func (c *Client) SignUp(ctx context.Context, username, password string) (int, error) {
+	var resp idResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/auth/sign-up", body: credentials{username, password}, public: true}, &resp)
+
+	return resp.Id, err
+}
+
+// SignIn returns an access token of the user and authorizes further requests with it.
+func (c *Client) SignIn(ctx context.Context, username, password string) (string, error) {
+	token, err := c.signIn(ctx, username, password)
+	if err != nil {
+		return "", err
+	}
+	c.SetToken(token)
+
+	return token, nil
+}
+
+func (c *Client) signIn(ctx context.Context, username, password string) (string, error) {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/auth/sign-in", body: credentials{username, password}, public: true}, &resp)
+
+	return resp.Token, err
+}
+
+// tokenExpiry reads the expiry of a JWT without verifying it, zero if the token has none.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.ExpiresAt, 0)
+}
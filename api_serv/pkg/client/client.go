@@ -0,0 +1,304 @@
+// Package client is a Go client of the shopping list API.
+//
+//	c := client.New("http://localhost", client.WithCredentials("user", "password"))
+//	lists, err := c.Lists(ctx, nil)
+//
+// With credentials the client signs in on the first request and again before its token
+// expires or when the API rejects it. Idempotent requests without a version to match are retried
+// with exponential backoff after network errors and temporary failures of the API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// defaultRetries is the number of retries of an idempotent request
+	defaultRetries = 3
+	// defaultMinBackoff and defaultMaxBackoff bound the delay before a retry
+	defaultMinBackoff = 200 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+	// refreshMargin is how long before the expiry a token is refreshed
+	refreshMargin = time.Minute
+)
+
+// Client calls the API on behalf of a user. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu       sync.Mutex
+	token    string
+	expiry   time.Time
+	username string
+	password string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends requests with the HTTP client instead of one with a 30 seconds timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.http = client
+	}
+}
+
+// WithToken authorizes requests with the access token.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.setToken(token)
+	}
+}
+
+// WithCredentials signs the user in when the client has no token or its token expires.
+func WithCredentials(username, password string) Option {
+	return func(c *Client) {
+		c.username, c.password = username, password
+	}
+}
+
+// WithRetries retries idempotent requests without a version to match up to retries times waiting from min to max
+// between attempts. Zero retries disable them.
+func WithRetries(retries int, min, max time.Duration) Option {
+	return func(c *Client) {
+		c.retries, c.minBackoff, c.maxBackoff = retries, min, max
+	}
+}
+
+// New returns a client of the API at the base URL like http://localhost.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		http:       &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// SetToken sets the access token sent with requests to the API.
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setToken(token)
+}
+
+// Token returns the access token of the client.
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token
+}
+
+func (c *Client) setToken(token string) {
+	c.token, c.expiry = token, tokenExpiry(token)
+}
+
+// request is a call of the API. IfMatch is the version of the changed object, 0 for none.
+type request struct {
+	method  string
+	path    string
+	query   url.Values
+	body    any
+	ifMatch int
+	// public requests are sent without a token
+	public bool
+}
+
+// idempotent reports whether repeating the request has the same effect as sending it once.
+// Conditional requests are not: when the response to one was lost after the change was made,
+// its retry fails as the version has changed.
+func (r request) idempotent() bool {
+	if r.ifMatch != 0 {
+		return false
+	}
+
+	return r.method == http.MethodGet || r.method == http.MethodHead || r.method == http.MethodPut ||
+		r.method == http.MethodDelete
+}
+
+// do sends the request, retrying it when it is idempotent and refreshing the token when
+// the API rejects it, and decodes the response into out unless it is nil.
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	var body []byte
+	if r.body != nil {
+		data, err := json.Marshal(r.body)
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		token, err := c.authorize(ctx, r, "")
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.send(ctx, r, body, token)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return decode(resp, out)
+		}
+
+		var retryAfter time.Duration
+		if err == nil {
+			err = responseError(resp)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+		}
+
+		if errors.Is(err, ErrUnauthorized) && !r.public && !refreshed && c.canSignIn() {
+			// the request was rejected before it was executed
+			refreshed = true
+			if _, err = c.authorize(ctx, r, token); err != nil {
+				return err
+			}
+			attempt--
+			continue
+		}
+
+		if ctx.Err() != nil || !r.idempotent() || attempt >= c.retries || !retryable(err) {
+			return err
+		}
+		if err = sleep(ctx, c.backoff(attempt, retryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) send(ctx context.Context, r request, body []byte, token string) (*http.Response, error) {
+	u := c.baseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if r.ifMatch != 0 {
+		req.Header.Set("If-Match", `"`+strconv.Itoa(r.ifMatch)+`"`)
+	}
+
+	return c.http.Do(req)
+}
+
+// authorize returns the token for the request, signing in with the credentials first when the
+// client has no token, the token expires soon or it is the rejected one. Concurrent requests
+// wait for the sign in, so a rejected token is replaced once.
+func (c *Client) authorize(ctx context.Context, r request, rejected string) (string, error) {
+	if r.public {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiring := !c.expiry.IsZero() && time.Until(c.expiry) < refreshMargin
+	valid := c.token != "" && !expiring && (rejected == "" || rejected != c.token)
+	if c.username == "" || valid {
+		return c.token, nil
+	}
+
+	token, err := c.signIn(ctx, c.username, c.password)
+	if err != nil {
+		return "", err
+	}
+	c.setToken(token)
+
+	return token, nil
+}
+
+func (c *Client) canSignIn() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.username != ""
+}
+
+// backoff returns the delay before the retry after the attempt: the delay the API asked for
+// or an exponentially growing one with full jitter.
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, c.maxBackoff)
+	}
+
+	delay := c.minBackoff << attempt
+	if delay < c.minBackoff || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return c.minBackoff/2 + time.Duration(rand.Int63n(int64(delay)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func parseRetryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at)
+	}
+
+	return 0
+}
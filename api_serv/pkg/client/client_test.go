@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer answers requests with the statuses in turn, repeating the last one, and counts them.
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	calls := new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status < http.StatusBadRequest {
+			w.Write([]byte(`{"id": 1}`))
+		} else {
+			w.Write([]byte(`{"message": "failed"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, calls
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		call     func(ctx context.Context, c *Client) error
+		calls    int32
+		err      error
+	}{
+		{
+			name:     "unavailable GET is retried",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.List(ctx, 1)
+				return err
+			},
+			calls: 3,
+		},
+		{
+			name:     "retries are limited",
+			statuses: []int{http.StatusServiceUnavailable},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.List(ctx, 1)
+				return err
+			},
+			calls: 4,
+			err:   ErrUnavailable,
+		},
+		{
+			name:     "client errors are not retried",
+			statuses: []int{http.StatusNotFound, http.StatusOK},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.List(ctx, 1)
+				return err
+			},
+			calls: 1,
+			err:   ErrNotFound,
+		},
+		{
+			name:     "POST is not retried",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.CreateList(ctx, List{Title: "Groceries"})
+				return err
+			},
+			calls: 1,
+			err:   ErrUnavailable,
+		},
+		{
+			name:     "conditional PUT is not retried",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			call: func(ctx context.Context, c *Client) error {
+				return c.UpdateItem(ctx, 1, UpdateItem{}, 2)
+			},
+			calls: 1,
+			err:   ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := statusServer(t, tt.statuses...)
+			c := New(server.URL, WithToken("token"), WithRetries(3, time.Millisecond, 2*time.Millisecond))
+
+			err := tt.call(context.Background(), c)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got := calls.Load(); got != tt.calls {
+				t.Fatalf("sent %d requests, want %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestSignInOnceForConcurrentRejections(t *testing.T) {
+	const requests = 8
+
+	var signIns atomic.Int32
+	rejected := make(chan struct{}, requests)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/auth/sign-in":
+			signIns.Add(1)
+			w.Write([]byte(`{"token": "fresh"}`))
+		case r.Header.Get("Authorization") == "Bearer fresh":
+			w.Write([]byte(`[]`))
+		default:
+			// hold the rejections until every request has sent the stale token
+			rejected <- struct{}{}
+			<-release
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "token is expired"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithToken("stale"), WithCredentials("user", "password"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Lists(context.Background(), nil)
+			errs <- err
+		}()
+	}
+	for i := 0; i < requests; i++ {
+		<-rejected
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+	}
+	if got := signIns.Load(); got != 1 {
+		t.Fatalf("signed in %d times, want once", got)
+	}
+	if token := c.Token(); token != "fresh" {
+		t.Fatalf("token is %q, want the fresh one", token)
+	}
+}
+
+func TestCancelDuringBackoff(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+	c := New(server.URL, WithToken("token"), WithRetries(3, time.Hour, time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.List(ctx, 1)
+		done <- err
+	}()
+
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got error %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the backoff was not interrupted by the cancellation")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("sent %d requests, want 1", got)
+	}
+}
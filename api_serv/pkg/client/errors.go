@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors of the API by the status code of its response, match them with errors.Is.
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrServer             = errors.New("server error")
+	ErrUnavailable        = errors.New("service unavailable")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusPreconditionFailed:  ErrPreconditionFailed,
+	http.StatusTooManyRequests:     ErrTooManyRequests,
+	http.StatusInternalServerError: ErrServer,
+	http.StatusBadGateway:          ErrUnavailable,
+	http.StatusServiceUnavailable:  ErrUnavailable,
+	http.StatusGatewayTimeout:      ErrUnavailable,
+}
+
+// Error is an error response of the API with the message of its body. It wraps the error
+// of its status code, ErrServer or ErrBadRequest for codes without one.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	return fmt.Sprintf("api: %d %s", e.StatusCode, message)
+}
+
+func (e *Error) Unwrap() error {
+	if err, ok := statusErrors[e.StatusCode]; ok {
+		return err
+	}
+	if e.StatusCode >= http.StatusInternalServerError {
+		return ErrServer
+	}
+
+	return ErrBadRequest
+}
+
+func responseError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) == nil {
+		apiErr.Message = body.Message
+	}
+
+	return apiErr
+}
+
+// retryable reports whether the request may succeed when it is sent again: the API was
+// unavailable, asked to slow down or could not be reached.
+func retryable(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrTooManyRequests)
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateItem adds the item to the list and returns its ID with a warning when the list
+// goes over its budget.
+func (c *Client) CreateItem(ctx context.Context, listId int, item Item) (int, string, error) {
+	var resp itemResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: listPath(listId) + "/items/", body: item}, &resp)
+
+	return resp.Id, resp.Warning, err
+}
+
+// QuickAdd adds the items written in free text like "2 kg potatoes, milk x3" to the list.
+func (c *Client) QuickAdd(ctx context.Context, listId int, text string) (QuickAddResult, error) {
+	body := struct {
+		Text string `json:"text"`
+	}{text}
+
+	var result QuickAddResult
+	err := c.do(ctx, request{method: http.MethodPost, path: listPath(listId) + "/items/quick", body: body}, &result)
+
+	return result, err
+}
+
+// Items returns the items of the list in user-defined order.
+func (c *Client) Items(ctx context.Context, listId int) ([]Item, error) {
+	var items []Item
+	err := c.do(ctx, request{method: http.MethodGet, path: listPath(listId) + "/items/"}, &items)
+
+	return items, err
+}
+
+// ItemsByCategory returns the items of the list grouped by category.
+func (c *Client) ItemsByCategory(ctx context.Context, listId int) ([]CategoryGroup, error) {
+	var groups []CategoryGroup
+	query := url.Values{"group": {"category"}}
+	err := c.do(ctx, request{method: http.MethodGet, path: listPath(listId) + "/items/", query: query}, &groups)
+
+	return groups, err
+}
+
+// ItemsInStoreOrder returns the items of the list in the walking order of the list store.
+func (c *Client) ItemsInStoreOrder(ctx context.Context, listId int) ([]Item, error) {
+	var items []Item
+	query := url.Values{"order": {"store"}}
+	err := c.do(ctx, request{method: http.MethodGet, path: listPath(listId) + "/items/", query: query}, &items)
+
+	return items, err
+}
+
+// Item returns the item.
+func (c *Client) Item(ctx context.Context, itemId int) (Item, error) {
+	var item Item
+	err := c.do(ctx, request{method: http.MethodGet, path: itemPath(itemId)}, &item)
+
+	return item, err
+}
+
+// UpdateItem changes the item if its version is still the version, any version for 0.
+func (c *Client) UpdateItem(ctx context.Context, itemId int, input UpdateItem, version int) error {
+	return c.do(ctx, request{method: http.MethodPut, path: itemPath(itemId), body: input, ifMatch: version}, nil)
+}
+
+// CheckItem checks the item off or unchecks it.
+func (c *Client) CheckItem(ctx context.Context, itemId int, checked bool) error {
+	return c.UpdateItem(ctx, itemId, UpdateItem{Checked: &checked}, 0)
+}
+
+// DeleteItem deletes the item if its version is still the version, any version for 0.
+func (c *Client) DeleteItem(ctx context.Context, itemId int, version int) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: itemPath(itemId), ifMatch: version}, nil)
+}
+
+// SetItemPosition places the item between its neighbours and returns its new position.
+func (c *Client) SetItemPosition(ctx context.Context, itemId int, input ItemPosition, version int) (string, error) {
+	var resp positionResponse
+	err := c.do(ctx, request{method: http.MethodPut, path: itemPath(itemId) + "/position", body: input, ifMatch: version}, &resp)
+
+	return resp.Position, err
+}
+
+// MoveItems moves the items into the list.
+func (c *Client) MoveItems(ctx context.Context, listId int, itemIds []int) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/items/move", body: transfer{listId, itemIds}}, nil)
+}
+
+// CopyItems copies the items into the list and returns the IDs of the copies.
+func (c *Client) CopyItems(ctx context.Context, listId int, itemIds []int) ([]int, error) {
+	var resp idsResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/items/copy", body: transfer{listId, itemIds}}, &resp)
+
+	return resp.Ids, err
+}
+
+// Bulk applies the operations to the items of the list in order in one transaction.
+func (c *Client) Bulk(ctx context.Context, listId int, operations []BulkOperation) ([]BulkResult, error) {
+	body := struct {
+		Operations []BulkOperation `json:"operations"`
+	}{operations}
+
+	var results []BulkResult
+	err := c.do(ctx, request{method: http.MethodPost, path: listPath(listId) + "/items/bulk", body: body}, &results)
+
+	return results, err
+}
+
+type transfer struct {
+	ListId  int   `json:"listId"`
+	ItemIds []int `json:"itemIds"`
+}
+
+func itemPath(itemId int) string {
+	return fmt.Sprintf("/api/items/%d", itemId)
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Lists returns the archived or the active lists, all of them for nil, in the order of the user.
+func (c *Client) Lists(ctx context.Context, archived *bool) ([]List, error) {
+	query := url.Values{"archived": {"all"}}
+	if archived != nil {
+		query.Set("archived", strconv.FormatBool(*archived))
+	}
+
+	var lists []List
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/lists/", query: query}, &lists)
+
+	return lists, err
+}
+
+// List returns the list with its totals.
+func (c *Client) List(ctx context.Context, listId int) (List, error) {
+	var list List
+	err := c.do(ctx, request{method: http.MethodGet, path: listPath(listId)}, &list)
+
+	return list, err
+}
+
+// CreateList creates the list and returns its ID.
+func (c *Client) CreateList(ctx context.Context, list List) (int, error) {
+	var resp idResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/lists/", body: list}, &resp)
+
+	return resp.Id, err
+}
+
+// UpdateList changes the list if its version is still the version, any version for 0.
+func (c *Client) UpdateList(ctx context.Context, listId int, input UpdateList, version int) error {
+	return c.do(ctx, request{method: http.MethodPut, path: listPath(listId), body: input, ifMatch: version}, nil)
+}
+
+// DeleteList deletes the list with its items if its version is still the version, any version for 0.
+func (c *Client) DeleteList(ctx context.Context, listId int, version int) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: listPath(listId), ifMatch: version}, nil)
+}
+
+// SetArchived archives the list or restores it.
+func (c *Client) SetArchived(ctx context.Context, listId int, archived bool) error {
+	action := "unarchive"
+	if archived {
+		action = "archive"
+	}
+
+	return c.do(ctx, request{method: http.MethodPost, path: listPath(listId) + "/" + action}, nil)
+}
+
+// ReorderLists orders the lists of the user as the IDs.
+func (c *Client) ReorderLists(ctx context.Context, ids []int) error {
+	body := struct {
+		Ids []int `json:"ids"`
+	}{ids}
+
+	return c.do(ctx, request{method: http.MethodPut, path: "/api/lists/order", body: body}, nil)
+}
+
+// DuplicateList copies the list and returns the ID of the copy.
+func (c *Client) DuplicateList(ctx context.Context, listId int, input DuplicateList) (int, error) {
+	var resp idResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: listPath(listId) + "/duplicate", body: input}, &resp)
+
+	return resp.Id, err
+}
+
+// MergeLists moves the items of the lists into the target list and deletes the emptied lists.
+func (c *Client) MergeLists(ctx context.Context, input MergeLists) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/lists/merge", body: input}, nil)
+}
+
+// SplitList moves the items into a new list and returns its ID.
+func (c *Client) SplitList(ctx context.Context, listId int, input SplitList) (int, error) {
+	var resp idResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: listPath(listId) + "/split", body: input}, &resp)
+
+	return resp.Id, err
+}
+
+func listPath(listId int) string {
+	return fmt.Sprintf("/api/lists/%d", listId)
+}
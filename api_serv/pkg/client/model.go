@@ -0,0 +1,151 @@
+package client
+
+// List is a shopping list. Budget and totals are in minor units of Currency.
+type List struct {
+	Id          int     `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Archived    bool    `json:"archived"`
+	Pinned      bool    `json:"pinned"`
+	Position    int     `json:"position"`
+	StoreId     *int    `json:"storeId"`
+	Budget      int64   `json:"budget"`
+	Currency    string  `json:"currency"`
+	Totals      *Totals `json:"totals,omitempty"`
+	Version     int     `json:"version"`
+}
+
+// Totals are the prices of the items of a list. Remaining is nil for lists without a budget.
+type Totals struct {
+	Estimated  int64  `json:"estimated"`
+	Spent      int64  `json:"spent"`
+	Pending    int64  `json:"pending"`
+	Remaining  *int64 `json:"remaining,omitempty"`
+	OverBudget bool   `json:"overBudget"`
+}
+
+// UpdateList changes the fields of a list that are not nil.
+type UpdateList struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Pinned      *bool   `json:"pinned,omitempty"`
+	StoreId     *int    `json:"storeId,omitempty"`
+	Budget      *int64  `json:"budget,omitempty"`
+	Currency    *string `json:"currency,omitempty"`
+}
+
+// DuplicateList copies a list with its items. Title defaults to the title of the copied list.
+type DuplicateList struct {
+	Title         string `json:"title,omitempty"`
+	UncheckedOnly bool   `json:"uncheckedOnly"`
+}
+
+// MergeLists moves the items of lists Ids into list TargetId and deletes the emptied lists.
+type MergeLists struct {
+	TargetId int   `json:"targetId"`
+	Ids      []int `json:"ids"`
+}
+
+// SplitList moves the items ItemIds into a new list with the title.
+type SplitList struct {
+	Title   string `json:"title"`
+	ItemIds []int  `json:"itemIds"`
+}
+
+// Item is an item of a list. Prices are for the whole quantity in minor units
+// of the list currency, 0 means the price is unknown.
+type Item struct {
+	Id             int     `json:"id"`
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	Checked        bool    `json:"checked"`
+	Quantity       float64 `json:"quantity"`
+	Unit           string  `json:"unit"`
+	Category       string  `json:"category"`
+	EstimatedPrice int64   `json:"estimatedPrice"`
+	ActualPrice    int64   `json:"actualPrice"`
+	Position       string  `json:"position"`
+	Version        int     `json:"version"`
+}
+
+// UpdateItem changes the fields of an item that are not nil.
+type UpdateItem struct {
+	Title          *string  `json:"title,omitempty"`
+	Description    *string  `json:"description,omitempty"`
+	Checked        *bool    `json:"checked,omitempty"`
+	Quantity       *float64 `json:"quantity,omitempty"`
+	Unit           *string  `json:"unit,omitempty"`
+	Category       *string  `json:"category,omitempty"`
+	EstimatedPrice *int64   `json:"estimatedPrice,omitempty"`
+	ActualPrice    *int64   `json:"actualPrice,omitempty"`
+}
+
+// ItemPosition places an item right after the item AfterId and before the item BeforeId,
+// one of them may be 0 to place it at the end or at the start of the list.
+type ItemPosition struct {
+	AfterId  int `json:"afterId,omitempty"`
+	BeforeId int `json:"beforeId,omitempty"`
+}
+
+// CategoryGroup holds the items of a list that belong to one category.
+type CategoryGroup struct {
+	Category string `json:"category"`
+	Items    []Item `json:"items"`
+}
+
+// QuickAddResult holds the items created from free text and the entries without a title.
+type QuickAddResult struct {
+	Items    []Item   `json:"items"`
+	Unparsed []string `json:"unparsed"`
+	Warning  string   `json:"warning,omitempty"`
+}
+
+// Operations of bulk item changes.
+const (
+	BulkCheckAll      = "checkAll"
+	BulkUncheckAll    = "uncheckAll"
+	BulkDeleteChecked = "deleteChecked"
+	BulkDelete        = "delete"
+	BulkUpdate        = "update"
+	BulkCreate        = "create"
+)
+
+// Outcomes of bulk item changes.
+const (
+	BulkApplied  = "applied"
+	BulkNotFound = "notFound"
+)
+
+// BulkOperation is a change of items of a list: Ids for delete and update,
+// Patch for update and Items for create.
+type BulkOperation struct {
+	Op    string      `json:"op"`
+	Ids   []int       `json:"ids,omitempty"`
+	Patch *UpdateItem `json:"patch,omitempty"`
+	Items []Item      `json:"items,omitempty"`
+}
+
+// BulkResult is the outcome of a bulk operation for an item.
+type BulkResult struct {
+	Op     string `json:"op"`
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+type idResponse struct {
+	Id int `json:"id"`
+}
+
+type idsResponse struct {
+	Ids []int `json:"ids"`
+}
+
+type itemResponse struct {
+	Id      int    `json:"id"`
+	Warning string `json:"warning"`
+}
+
+type positionResponse struct {
+	Id       int    `json:"id"`
+	Position string `json:"position"`
+}
@@ -0,0 +1,295 @@
+// Package search finds strings of a fixed collection by a query. Matching ignores case,
+// repeated spaces, diacritics of non-Cyrillic letters and the difference of ё and е, so "ЁЖИК"
+// matches "ежик" and "cafe" matches "café", but "чаи" does not match "чай". Empty queries match
+// nothing. Prefix queries use binary search over the sorted keys and substring queries use
+// an index of trigrams of the keys.
+package search
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// gram is the length of the n-grams of the index in runes.
+const gram = 3
+
+// diaeresis is the combining mark of ё decomposed.
+const diaeresis = '\u0308'
+
+// Kinds of matches from the best to the worst.
+const (
+	KindExact     = "exact"
+	KindPrefix    = "prefix"
+	KindWord      = "word"
+	KindSubstring = "substring"
+)
+
+var kindScores = map[string]float64{KindExact: 4, KindPrefix: 3, KindWord: 2, KindSubstring: 1}
+
+// Match is a string of the collection matching the query. Index is its position in the collection,
+// Offset is the position of the query in the normalized string in runes.
+type Match struct {
+	Index  int
+	Text   string
+	Kind   string
+	Offset int
+	Score  float64
+}
+
+type entry struct {
+	key   string
+	runes int
+	index int
+}
+
+// Index is an immutable index of a collection of strings.
+type Index struct {
+	texts []string
+	// entries are sorted by their keys
+	entries []entry
+	// grams holds the positions in entries of the keys containing each n-gram, in ascending order
+	grams map[string][]int
+}
+
+// New indexes the strings.
+func New(texts []string) *Index {
+	x := &Index{
+		texts:   append([]string(nil), texts...),
+		entries: make([]entry, len(texts)),
+		grams:   make(map[string][]int),
+	}
+
+	for i, text := range texts {
+		key := Normalize(text)
+		x.entries[i] = entry{key: key, runes: len([]rune(key)), index: i}
+	}
+	sort.SliceStable(x.entries, func(i, j int) bool {
+		return x.entries[i].key < x.entries[j].key
+	})
+
+	for i, e := range x.entries {
+		for _, g := range ngrams(e.key) {
+			postings := x.grams[g]
+			if len(postings) == 0 || postings[len(postings)-1] != i {
+				x.grams[g] = append(postings, i)
+			}
+		}
+	}
+
+	return x
+}
+
+// Len returns the number of strings in the index.
+func (x *Index) Len() int {
+	return len(x.texts)
+}
+
+// Prefix returns all strings starting with the query in the order of their keys.
+func (x *Index) Prefix(query string) []Match {
+	query = Normalize(query)
+	matches := make([]Match, 0)
+	if query == "" {
+		return matches
+	}
+
+	start := sort.Search(len(x.entries), func(i int) bool {
+		return x.entries[i].key >= query
+	})
+	for i := start; i < len(x.entries) && strings.HasPrefix(x.entries[i].key, query); i++ {
+		e := x.entries[i]
+		kind := KindPrefix
+		if e.key == query {
+			kind = KindExact
+		}
+		matches = append(matches, x.match(e, kind, 0, len([]rune(query))))
+	}
+
+	return matches
+}
+
+// Search returns all strings containing the query, the best matches first: exact ones,
+// then the ones starting with the query, having a word starting with it and containing it
+// elsewhere. Matches of a kind are ranked by how early and how large a part of the string the query is.
+func (x *Index) Search(query string) []Match {
+	query = Normalize(query)
+	length := len([]rune(query))
+	matches := make([]Match, 0)
+	if query == "" {
+		return matches
+	}
+
+	for _, i := range x.candidates(query) {
+		e := x.entries[i]
+		at := strings.Index(e.key, query)
+		if at < 0 {
+			continue
+		}
+
+		kind := KindSubstring
+		switch {
+		case e.key == query:
+			kind = KindExact
+		case at == 0:
+			kind = KindPrefix
+		default:
+			if word := wordStart(e.key, query); word >= 0 {
+				kind, at = KindWord, word
+			}
+		}
+		matches = append(matches, x.match(e, kind, len([]rune(e.key[:at])), length))
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Index < matches[j].Index
+	})
+
+	return matches
+}
+
+// First returns the best match of the query.
+func (x *Index) First(query string) (Match, bool) {
+	matches := x.Search(query)
+	if len(matches) == 0 {
+		return Match{}, false
+	}
+
+	return matches[0], true
+}
+
+// candidates returns the positions of entries that contain all n-grams of the query,
+// every entry for queries shorter than an n-gram.
+func (x *Index) candidates(query string) []int {
+	grams := ngrams(query)
+	if len(grams) == 0 {
+		all := make([]int, len(x.entries))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+
+	lists := make([][]int, 0, len(grams))
+	for _, g := range grams {
+		postings, ok := x.grams[g]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, postings)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+
+	result := lists[0]
+	for _, postings := range lists[1:] {
+		result = intersect(result, postings)
+		if len(result) == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// match scores the match of a kind at the offset: the kind first, then the earlier
+// and the larger part of the string the query covers the better.
+func (x *Index) match(e entry, kind string, offset, length int) Match {
+	score := kindScores[kind]
+	if e.runes > 0 {
+		score += 0.5*float64(length)/float64(e.runes) + 0.49/float64(1+offset)
+	}
+
+	return Match{Index: e.index, Text: x.texts[e.index], Kind: kind, Offset: offset, Score: score}
+}
+
+// Normalize returns the string in lower case without surrounding and repeated spaces, with ё
+// folded to е and diacritics removed from letters other than Cyrillic ones, so й is kept.
+func Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	var base rune
+	for _, r := range norm.NFKD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			if unicode.Is(unicode.Cyrillic, base) && !(base == 'е' && r == diaeresis) {
+				b.WriteRune(r)
+			}
+			continue
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		base = unicode.ToLower(r)
+		b.WriteRune(base)
+	}
+
+	return norm.NFC.String(b.String())
+}
+
+// ngrams returns the distinct n-grams of the key.
+func ngrams(key string) []string {
+	runes := []rune(key)
+	grams := make([]string, 0, max(len(runes)-gram+1, 0))
+	seen := make(map[string]bool)
+	for i := 0; i+gram <= len(runes); i++ {
+		g := string(runes[i : i+gram])
+		if !seen[g] {
+			seen[g] = true
+			grams = append(grams, g)
+		}
+	}
+
+	return grams
+}
+
+// wordStart returns the byte position of the first word of the key starting with the query.
+func wordStart(key, query string) int {
+	for at := 0; at < len(key); {
+		i := strings.Index(key[at:], query)
+		if i < 0 {
+			return -1
+		}
+		i += at
+		if i == 0 || !endsWithWord(key[:i]) {
+			return i
+		}
+		at = i + 1
+	}
+
+	return -1
+}
+
+// endsWithWord reports whether the string ends with a letter or a digit.
+func endsWithWord(s string) bool {
+	runes := []rune(s)
+	r := runes[len(runes)-1]
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func intersect(a, b []int) []int {
+	result := make([]int, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+
+	return result
+}
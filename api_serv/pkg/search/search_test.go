@@ -0,0 +1,148 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"Milk", "milk"},
+		{"  whole   milk \t", "whole milk"},
+		{"line\nbreak", "line break"},
+		{"Café", "cafe"},
+		{"Crème Brûlée", "creme brulee"},
+		{"ЁЖИК", "ежик"},
+		{"Зелёный чай", "зеленый чай"},
+		{"ЙОГУРТ", "йогурт"},
+		{"чай", "чай"},
+		{"", ""},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.s); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+// matched returns the texts and kinds of the matches.
+func matched(matches []Match) [][2]string {
+	result := make([][2]string, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, [2]string{m.Text, m.Kind})
+	}
+
+	return result
+}
+
+func TestPrefix(t *testing.T) {
+	index := New([]string{"mint", "Milk chocolate", "молочный шоколад", "Buttermilk", "Молоко", "milk", "almond milk"})
+
+	tests := []struct {
+		query string
+		want  [][2]string
+	}{
+		{"MIL", [][2]string{{"milk", KindPrefix}, {"Milk chocolate", KindPrefix}}},
+		{"milk", [][2]string{{"milk", KindExact}, {"Milk chocolate", KindPrefix}}},
+		{"m", [][2]string{{"milk", KindPrefix}, {"Milk chocolate", KindPrefix}, {"mint", KindPrefix}}},
+		{"мол", [][2]string{{"Молоко", KindPrefix}, {"молочный шоколад", KindPrefix}}},
+		{"butter", [][2]string{{"Buttermilk", KindPrefix}}},
+		{"chocolate", [][2]string{}},
+		{"zzz", [][2]string{}},
+		{"", [][2]string{}},
+		{"  ", [][2]string{}},
+	}
+
+	for _, tt := range tests {
+		if got := matched(index.Prefix(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Prefix(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	index := New([]string{"chocolate milk", "Milk", "buttermilk", "milk chocolate", "almond milk drink", "bread"})
+
+	matches := index.Search("milk")
+	want := [][2]string{
+		{"Milk", KindExact},
+		{"milk chocolate", KindPrefix},
+		// the earlier and the larger part of the string the better
+		{"chocolate milk", KindWord},
+		{"almond milk drink", KindWord},
+		{"buttermilk", KindSubstring},
+	}
+	if got := matched(matches); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Search(milk) = %v, want %v", got, want)
+	}
+
+	offsets := []int{0, 0, 10, 7, 6}
+	indexes := []int{1, 3, 0, 4, 2}
+	for i, m := range matches {
+		if m.Offset != offsets[i] || m.Index != indexes[i] {
+			t.Errorf("match %q has offset %d and index %d, want %d and %d", m.Text, m.Offset, m.Index, offsets[i], indexes[i])
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	index := New([]string{"abcxbcd", "abcd", "Ёжик в тумане", "чай", "xyz ab", "ab"})
+
+	tests := []struct {
+		query string
+		want  [][2]string
+	}{
+		// the n-grams of the query are in "abcxbcd" too, but not the query itself
+		{"abcd", [][2]string{{"abcd", KindExact}}},
+		{"bcd", [][2]string{{"abcd", KindSubstring}, {"abcxbcd", KindSubstring}}},
+		// queries shorter than an n-gram are looked up in every string
+		{"ab", [][2]string{{"ab", KindExact}, {"abcd", KindPrefix}, {"abcxbcd", KindPrefix}, {"xyz ab", KindWord}}},
+		{"d", [][2]string{{"abcd", KindSubstring}, {"abcxbcd", KindSubstring}}},
+		{"ЕЖИК", [][2]string{{"Ёжик в тумане", KindPrefix}}},
+		{"в тумане", [][2]string{{"Ёжик в тумане", KindWord}}},
+		{"чаи", [][2]string{}},
+		{"", [][2]string{}},
+		{" \t", [][2]string{}},
+	}
+
+	for _, tt := range tests {
+		if got := matched(index.Search(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if _, ok := index.First(""); ok {
+		t.Error("First of an empty query must find nothing")
+	}
+	if m, ok := index.First("чай"); !ok || m.Text != "чай" {
+		t.Errorf("First(чай) = %v, %v, want чай", m, ok)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	index := New([]string{"abcxbcd", "abcd", "xyz"})
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"abcd", 2},
+		{"bcd", 2},
+		{"xyz", 1},
+		{"bcx", 1},
+		{"abz", 0},
+		{"ab", 3},
+		{"q", 3},
+	}
+
+	for _, tt := range tests {
+		if got := len(index.candidates(tt.query)); got != tt.want {
+			t.Errorf("candidates(%q) has %d entries, want %d", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE "Snapshot_Item";
+DROP TABLE "List_Snapshot";
+
+COMMIT;
\ No newline at end of file
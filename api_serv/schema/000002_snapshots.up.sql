@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE "List_Snapshot"
+(
+    "ID"          BigSerial                                                  NOT NULL PRIMARY KEY,
+    "List_id"     BigInt REFERENCES "Shopping_List" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"       Character varying(100)                                     NOT NULL,
+    "Description" Text,
+    "Created_at"  Timestamp with time zone                                   NOT NULL DEFAULT now()
+) WITH (autovacuum_enabled = true);
+
+CREATE TABLE "Snapshot_Item"
+(
+    "ID"          BigSerial                                                  NOT NULL PRIMARY KEY,
+    "Snapshot_id" BigInt REFERENCES "List_Snapshot" ("ID") ON DELETE CASCADE NOT NULL,
+    "Item_id"     BigInt                                                     NOT NULL,
+    "Title"       Character varying(100)                                     NOT NULL,
+    "Description" Text,
+    "Checked"     Boolean                                                    NOT NULL DEFAULT false
+) WITH (autovacuum_enabled = true);
+
+COMMIT;
\ No newline at end of file
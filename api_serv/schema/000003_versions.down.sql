@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Updated_at",
+    DROP COLUMN "Version";
+
+ALTER TABLE "Shopping_List"
+    DROP COLUMN "Updated_at",
+    DROP COLUMN "Version";
+
+COMMIT;
\ No newline at end of file
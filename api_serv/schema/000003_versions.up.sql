@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE "Shopping_List"
+    ADD COLUMN "Version"    BigInt                   NOT NULL DEFAULT 1,
+    ADD COLUMN "Updated_at" Timestamp with time zone NOT NULL DEFAULT now();
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Version"    BigInt                   NOT NULL DEFAULT 1,
+    ADD COLUMN "Updated_at" Timestamp with time zone NOT NULL DEFAULT now();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TRIGGER "Shopping_Item_change_log" ON "Shopping_Item";
+DROP TRIGGER "Shopping_List_change_log" ON "Shopping_List";
+DROP FUNCTION log_item_change();
+DROP FUNCTION log_list_change();
+DROP TABLE "Sync_Client_Id";
+DROP TABLE "Change_Log";
+
+COMMIT;
\ No newline at end of file
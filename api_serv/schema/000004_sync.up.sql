@@ -0,0 +1,71 @@
+BEGIN;
+
+CREATE TABLE "Change_Log"
+(
+    "ID"         BigSerial                                         NOT NULL PRIMARY KEY,
+    "User_id"    BigInt                                            NOT NULL,
+    "Entity"     Character varying(20)                             NOT NULL,
+    "Entity_id"  BigInt                                            NOT NULL,
+    "List_id"    BigInt                                            NOT NULL,
+    "Deleted"    Boolean                                           NOT NULL DEFAULT false,
+    "Changed_at" Timestamp with time zone                          NOT NULL DEFAULT now()
+) WITH (autovacuum_enabled = true);
+
+CREATE INDEX "Change_Log_User_id_ID_idx" ON "Change_Log" ("User_id", "ID");
+
+CREATE TABLE "Sync_Client_Id"
+(
+    "User_id"   BigInt REFERENCES "User" ("ID") ON DELETE CASCADE NOT NULL,
+    "Client_id" Character varying(100)                            NOT NULL,
+    "Entity"    Character varying(20)                             NOT NULL,
+    "Entity_id" BigInt                                            NOT NULL,
+    PRIMARY KEY ("User_id", "Client_id")
+) WITH (autovacuum_enabled = true);
+
+CREATE FUNCTION log_list_change() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO "Change_Log" ("User_id", "Entity", "Entity_id", "List_id", "Deleted")
+        VALUES (OLD."User_id", 'list', OLD."ID", OLD."ID", true);
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO "Change_Log" ("User_id", "Entity", "Entity_id", "List_id")
+    VALUES (NEW."User_id", 'list', NEW."ID", NEW."ID");
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Items deleted together with their list are not logged: the list tombstone covers them.
+CREATE FUNCTION log_item_change() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND OLD."List_id" <> NEW."List_id") THEN
+        INSERT INTO "Change_Log" ("User_id", "Entity", "Entity_id", "List_id", "Deleted")
+        SELECT L."User_id", 'item', OLD."ID", OLD."List_id", true FROM "Shopping_List" L WHERE L."ID" = OLD."List_id";
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO "Change_Log" ("User_id", "Entity", "Entity_id", "List_id")
+    SELECT L."User_id", 'item', NEW."ID", NEW."List_id" FROM "Shopping_List" L WHERE L."ID" = NEW."List_id";
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Shopping_List_change_log"
+    AFTER INSERT OR UPDATE OR DELETE
+    ON "Shopping_List"
+    FOR EACH ROW
+EXECUTE FUNCTION log_list_change();
+
+CREATE TRIGGER "Shopping_Item_change_log"
+    AFTER INSERT OR UPDATE OR DELETE
+    ON "Shopping_Item"
+    FOR EACH ROW
+EXECUTE FUNCTION log_item_change();
+
+COMMIT;
\ No newline at end of file
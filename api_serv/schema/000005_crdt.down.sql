@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE "Item_Tag";
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Clocks";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Clocks" jsonb NOT NULL DEFAULT '{}';
+
+CREATE TABLE "Item_Tag"
+(
+    "Item_id" BigInt                                                     NOT NULL,
+    "List_id" BigInt REFERENCES "Shopping_List" ("ID") ON DELETE CASCADE NOT NULL,
+    "Tag"     Character varying(100)                                     NOT NULL,
+    "Removed" Boolean                                                    NOT NULL DEFAULT false,
+    PRIMARY KEY ("Item_id", "Tag")
+) WITH (autovacuum_enabled = true);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE "Shopping_List"
+    DROP COLUMN "Position",
+    DROP COLUMN "Pinned",
+    DROP COLUMN "Archived";
+
+COMMIT;
\ No newline at end of file
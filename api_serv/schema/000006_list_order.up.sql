@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE "Shopping_List"
+    ADD COLUMN "Archived" Boolean NOT NULL DEFAULT false,
+    ADD COLUMN "Pinned"   Boolean NOT NULL DEFAULT false,
+    ADD COLUMN "Position" Integer NOT NULL DEFAULT 0;
+
+UPDATE "Shopping_List" L
+SET "Position" = O."Position"
+FROM (SELECT "ID", ROW_NUMBER() OVER (PARTITION BY "User_id" ORDER BY "ID") AS "Position" FROM "Shopping_List") O
+WHERE L."ID" = O."ID";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE "Template_Item";
+DROP TABLE "List_Template";
+
+COMMIT;
\ No newline at end of file
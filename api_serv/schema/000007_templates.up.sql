@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE "List_Template"
+(
+    "ID"          BigSerial                                         NOT NULL PRIMARY KEY,
+    "User_id"     BigInt REFERENCES "User" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"       Character varying(100)                            NOT NULL,
+    "Description" Text                                              NOT NULL DEFAULT ''
+) WITH (autovacuum_enabled = true);
+
+CREATE TABLE "Template_Item"
+(
+    "ID"          BigSerial                                                  NOT NULL PRIMARY KEY,
+    "Template_id" BigInt REFERENCES "List_Template" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"       Character varying(100)                                     NOT NULL,
+    "Description" Text                                                       NOT NULL DEFAULT '',
+    "Quantity"    Numeric(12, 3)                                             NOT NULL DEFAULT 1,
+    "Position"    Integer                                                    NOT NULL DEFAULT 0
+) WITH (autovacuum_enabled = true);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE "List_Schedule";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE "List_Schedule"
+(
+    "ID"          BigSerial                                                  NOT NULL PRIMARY KEY,
+    "User_id"     BigInt REFERENCES "User" ("ID") ON DELETE CASCADE          NOT NULL,
+    "Template_id" BigInt REFERENCES "List_Template" ("ID") ON DELETE CASCADE NOT NULL,
+    "List_id"     BigInt REFERENCES "Shopping_List" ("ID") ON DELETE CASCADE,
+    "Title"       Character varying(100)                                     NOT NULL DEFAULT '',
+    "Rule"        Character varying(200)                                     NOT NULL,
+    "Start"       Timestamp with time zone                                   NOT NULL,
+    "Timezone"    Character varying(64)                                      NOT NULL DEFAULT 'UTC',
+    "Enabled"     Boolean                                                    NOT NULL DEFAULT true,
+    "Next_run"    Timestamp with time zone,
+    "Last_run"    Timestamp with time zone
+) WITH (autovacuum_enabled = true);
+
+CREATE INDEX "List_Schedule_Next_run_idx" ON "List_Schedule" ("Next_run") WHERE "Enabled";
+
+COMMIT;
\ No newline at end of file
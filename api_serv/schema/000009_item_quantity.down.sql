@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Quantity";
+
+COMMIT;
\ No newline at end of file
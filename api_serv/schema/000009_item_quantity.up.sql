@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Quantity" Numeric(12, 3) NOT NULL DEFAULT 1;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Unit" Character varying(10) NOT NULL DEFAULT 'pcs';
+
+COMMIT;
\ No newline at end of file
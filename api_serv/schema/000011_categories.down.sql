@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE "Category_Dictionary";
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Category";
+
+COMMIT;
\ No newline at end of file
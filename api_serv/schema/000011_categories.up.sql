@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Category" Character varying(30) NOT NULL DEFAULT '';
+
+CREATE TABLE "Category_Dictionary"
+(
+    "User_id"  BigInt REFERENCES "User" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"    Character varying(100)                            NOT NULL,
+    "Category" Character varying(30)                             NOT NULL,
+    PRIMARY KEY ("User_id", "Title")
+) WITH (autovacuum_enabled = true);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,15 @@
+BEGIN;
+
+DROP TRIGGER "Shopping_Item_store_order" ON "Shopping_Item";
+DROP FUNCTION learn_store_order();
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Checked_at";
+
+ALTER TABLE "Shopping_List"
+    DROP COLUMN "Store_id";
+
+DROP TABLE "Store_Rank";
+DROP TABLE "Store";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,68 @@
+BEGIN;
+
+CREATE TABLE "Store"
+(
+    "ID"      BigSerial                                         NOT NULL PRIMARY KEY,
+    "User_id" BigInt REFERENCES "User" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"   Character varying(100)                            NOT NULL,
+    "Aisles"  Character varying(30)[]                           NOT NULL DEFAULT '{}'
+) WITH (autovacuum_enabled = true);
+
+-- Rank is the average position at which the product is checked off in the store
+CREATE TABLE "Store_Rank"
+(
+    "Store_id" BigInt REFERENCES "Store" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"    Character varying(100)                             NOT NULL,
+    "Rank"     Double precision                                   NOT NULL,
+    "Samples"  Integer                                            NOT NULL DEFAULT 1,
+    PRIMARY KEY ("Store_id", "Title")
+) WITH (autovacuum_enabled = true);
+
+ALTER TABLE "Shopping_List"
+    ADD COLUMN "Store_id" BigInt REFERENCES "Store" ("ID") ON DELETE SET NULL;
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Checked_at" Timestamp with time zone;
+
+-- Items checked off within three hours belong to one walk through the store. The rank
+-- moves towards the latest position, recent walks weigh at least a tenth.
+CREATE FUNCTION learn_store_order() RETURNS trigger AS
+$$
+DECLARE
+    store          BigInt;
+    checked_before Integer;
+BEGIN
+    IF NOT NEW."Checked" THEN
+        NEW."Checked_at" := NULL;
+        RETURN NEW;
+    END IF;
+    IF OLD."Checked" THEN
+        RETURN NEW;
+    END IF;
+
+    NEW."Checked_at" := now();
+
+    SELECT L."Store_id" INTO store FROM "Shopping_List" L WHERE L."ID" = NEW."List_id";
+    IF store IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT count(*) INTO checked_before FROM "Shopping_Item" I
+    WHERE I."List_id" = NEW."List_id" AND I."ID" <> NEW."ID" AND I."Checked" AND I."Checked_at" > now() - interval '3 hours';
+
+    INSERT INTO "Store_Rank" ("Store_id", "Title", "Rank")
+    VALUES (store, lower(regexp_replace(btrim(NEW."Title"), '\s+', ' ', 'g')), checked_before)
+    ON CONFLICT ("Store_id", "Title") DO UPDATE
+        SET "Rank"    = "Store_Rank"."Rank" + (EXCLUDED."Rank" - "Store_Rank"."Rank") / LEAST("Store_Rank"."Samples" + 1, 10),
+            "Samples" = "Store_Rank"."Samples" + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Shopping_Item_store_order"
+    BEFORE UPDATE OF "Checked"
+    ON "Shopping_Item"
+    FOR EACH ROW
+EXECUTE FUNCTION learn_store_order();
+
+COMMIT;
\ No newline at end of file
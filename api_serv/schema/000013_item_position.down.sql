@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TRIGGER "Shopping_Item_position" ON "Shopping_Item";
+DROP FUNCTION append_item_position();
+DROP FUNCTION item_rank_after(Text);
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Position";
+
+COMMIT;
\ No newline at end of file
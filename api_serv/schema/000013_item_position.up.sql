@@ -0,0 +1,63 @@
+BEGIN;
+
+-- Positions are compared bytewise, see model.RankBetween
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Position" Character varying COLLATE "C" NOT NULL DEFAULT '';
+
+-- item_rank_after returns a short position after the passed one, it matches model.RankAfter
+CREATE FUNCTION item_rank_after(rank_key Text) RETURNS Text AS
+$$
+DECLARE
+    digits CONSTANT Text := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+    i               Integer;
+BEGIN
+    FOR i IN REVERSE length(rank_key)..1
+        LOOP
+            IF substr(rank_key, i, 1) <> 'z' THEN
+                RETURN substr(rank_key, 1, i - 1) || substr(digits, strpos(digits, substr(rank_key, i, 1)) + 1, 1);
+            END IF;
+        END LOOP;
+    RETURN rank_key || 'V';
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+DO
+$$
+DECLARE
+    item     RECORD;
+    list_id  BigInt;
+    rank_key Text;
+BEGIN
+    FOR item IN SELECT "ID", "List_id" FROM "Shopping_Item" ORDER BY "List_id", "ID"
+        LOOP
+            IF list_id IS DISTINCT FROM item."List_id" THEN
+                list_id := item."List_id";
+                rank_key := '';
+            END IF;
+            rank_key := item_rank_after(rank_key);
+            UPDATE "Shopping_Item" SET "Position" = rank_key WHERE "ID" = item."ID";
+        END LOOP;
+END;
+$$;
+
+-- An empty position appends the item to the end of its list
+CREATE FUNCTION append_item_position() RETURNS trigger AS
+$$
+BEGIN
+    IF NEW."Position" = '' THEN
+        NEW."Position" := item_rank_after(COALESCE((SELECT max(I."Position") FROM "Shopping_Item" I
+                                                    WHERE I."List_id" = NEW."List_id" AND I."ID" <> NEW."ID"), ''));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Shopping_Item_position"
+    BEFORE INSERT OR UPDATE OF "Position", "List_id"
+    ON "Shopping_Item"
+    FOR EACH ROW
+EXECUTE FUNCTION append_item_position();
+
+CREATE INDEX "Shopping_Item_List_id_Position_idx" ON "Shopping_Item" ("List_id", "Position");
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Estimated_price",
+    DROP COLUMN "Actual_price";
+
+ALTER TABLE "Shopping_List"
+    DROP COLUMN "Budget",
+    DROP COLUMN "Currency";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Prices and budgets are in minor units of the list currency, 0 means not set
+ALTER TABLE "Shopping_List"
+    ADD COLUMN "Budget"   BigInt               NOT NULL DEFAULT 0 CHECK ("Budget" >= 0),
+    ADD COLUMN "Currency" Character varying(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Estimated_price" BigInt NOT NULL DEFAULT 0 CHECK ("Estimated_price" >= 0),
+    ADD COLUMN "Actual_price"    BigInt NOT NULL DEFAULT 0 CHECK ("Actual_price" >= 0);
+
+COMMIT;
\ No newline at end of file
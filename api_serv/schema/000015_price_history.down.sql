@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER "Shopping_Item_price_observation" ON "Shopping_Item";
+DROP FUNCTION record_price_observation();
+
+DROP TABLE "Price_Observation";
+DROP TABLE "Product";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,56 @@
+BEGIN;
+
+CREATE TABLE "Product"
+(
+    "ID"      BigSerial                                         NOT NULL PRIMARY KEY,
+    "User_id" BigInt REFERENCES "User" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"   Character varying(100)                            NOT NULL,
+    UNIQUE ("User_id", "Title")
+) WITH (autovacuum_enabled = true);
+
+CREATE TABLE "Price_Observation"
+(
+    "ID"          BigSerial                                            NOT NULL PRIMARY KEY,
+    "Product_id"  BigInt REFERENCES "Product" ("ID") ON DELETE CASCADE NOT NULL,
+    "Store_id"    BigInt REFERENCES "Store" ("ID") ON DELETE SET NULL,
+    "Price"       BigInt                                               NOT NULL,
+    "Quantity"    Numeric(12, 3)                                       NOT NULL,
+    "Unit"        Character varying(10)                                NOT NULL,
+    "Currency"    Character varying(3)                                 NOT NULL,
+    "Observed_at" Timestamp with time zone                             NOT NULL DEFAULT now()
+) WITH (autovacuum_enabled = true);
+
+CREATE INDEX "Price_Observation_Product_id_Observed_at_idx" ON "Price_Observation" ("Product_id", "Observed_at");
+
+-- An item checked with an actual price is an observation of the price of its product
+-- in the store of the list. The product title matches service.categoryKey.
+CREATE FUNCTION record_price_observation() RETURNS trigger AS
+$$
+DECLARE
+    list    RECORD;
+    product BigInt;
+BEGIN
+    IF NOT NEW."Checked" OR NEW."Actual_price" = 0 OR OLD."Checked" AND OLD."Actual_price" = NEW."Actual_price" THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT L."User_id", L."Store_id", L."Currency" INTO list FROM "Shopping_List" L WHERE L."ID" = NEW."List_id";
+
+    INSERT INTO "Product" ("User_id", "Title")
+    VALUES (list."User_id", lower(regexp_replace(btrim(NEW."Title"), '\s+', ' ', 'g')))
+    ON CONFLICT ("User_id", "Title") DO UPDATE SET "Title" = EXCLUDED."Title"
+    RETURNING "ID" INTO product;
+
+    INSERT INTO "Price_Observation" ("Product_id", "Store_id", "Price", "Quantity", "Unit", "Currency")
+    VALUES (product, list."Store_id", NEW."Actual_price", NEW."Quantity", NEW."Unit", list."Currency");
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Shopping_Item_price_observation"
+    AFTER UPDATE OF "Checked", "Actual_price"
+    ON "Shopping_Item"
+    FOR EACH ROW
+EXECUTE FUNCTION record_price_observation();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER "Shopping_Item_trip_purchase" ON "Shopping_Item";
+DROP FUNCTION record_trip_purchase();
+
+DROP TABLE "Purchase";
+DROP TABLE "Trip";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,77 @@
+BEGIN;
+
+CREATE TABLE "Trip"
+(
+    "ID"          BigSerial                                                  NOT NULL PRIMARY KEY,
+    "User_id"     BigInt REFERENCES "User" ("ID") ON DELETE CASCADE          NOT NULL,
+    "List_id"     BigInt REFERENCES "Shopping_List" ("ID") ON DELETE SET NULL,
+    "Store_id"    BigInt REFERENCES "Store" ("ID") ON DELETE SET NULL,
+    "Currency"    Character varying(3)                                       NOT NULL,
+    "Started_at"  Timestamp with time zone                                   NOT NULL DEFAULT now(),
+    "Finished_at" Timestamp with time zone
+) WITH (autovacuum_enabled = true);
+
+-- A list has at most one active trip
+CREATE UNIQUE INDEX "Trip_List_id_active_idx" ON "Trip" ("List_id") WHERE "Finished_at" IS NULL;
+CREATE INDEX "Trip_User_id_Started_at_idx" ON "Trip" ("User_id", "Started_at");
+
+CREATE TABLE "Purchase"
+(
+    "ID"         BigSerial                                         NOT NULL PRIMARY KEY,
+    "Trip_id"    BigInt REFERENCES "Trip" ("ID") ON DELETE CASCADE NOT NULL,
+    "Item_id"    BigInt REFERENCES "Shopping_Item" ("ID") ON DELETE SET NULL,
+    "Title"      Character varying(100)                            NOT NULL,
+    "Quantity"   Numeric(12, 3)                                    NOT NULL,
+    "Unit"       Character varying(10)                             NOT NULL,
+    "Category"   Character varying(30)                             NOT NULL,
+    "Price"      BigInt                                            NOT NULL,
+    "Checked_at" Timestamp with time zone                          NOT NULL DEFAULT now(),
+    UNIQUE ("Trip_id", "Item_id")
+) WITH (autovacuum_enabled = true);
+
+-- Items checked off while their list has an active trip are recorded as its purchases,
+-- unchecking an item takes it back. The price is the actual price, the estimated one without it.
+CREATE FUNCTION record_trip_purchase() RETURNS trigger AS
+$$
+DECLARE
+    trip BigInt;
+BEGIN
+    IF OLD."Checked" = NEW."Checked" AND NOT NEW."Checked" THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT T."ID" INTO trip FROM "Trip" T WHERE T."List_id" = NEW."List_id" AND T."Finished_at" IS NULL;
+    IF trip IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF NOT NEW."Checked" THEN
+        DELETE FROM "Purchase" P WHERE P."Trip_id" = trip AND P."Item_id" = NEW."ID";
+        RETURN NULL;
+    END IF;
+
+    IF OLD."Checked" AND NOT EXISTS(SELECT FROM "Purchase" P WHERE P."Trip_id" = trip AND P."Item_id" = NEW."ID") THEN
+        -- checked before the trip started
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO "Purchase" ("Trip_id", "Item_id", "Title", "Quantity", "Unit", "Category", "Price")
+    VALUES (trip, NEW."ID", NEW."Title", NEW."Quantity", NEW."Unit", NEW."Category",
+            COALESCE(NULLIF(NEW."Actual_price", 0), NEW."Estimated_price"))
+    ON CONFLICT ("Trip_id", "Item_id") DO UPDATE
+        SET "Title"    = EXCLUDED."Title",
+            "Quantity" = EXCLUDED."Quantity",
+            "Unit"     = EXCLUDED."Unit",
+            "Category" = EXCLUDED."Category",
+            "Price"    = EXCLUDED."Price";
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Shopping_Item_trip_purchase"
+    AFTER UPDATE OF "Checked", "Title", "Quantity", "Unit", "Category", "Estimated_price", "Actual_price"
+    ON "Shopping_Item"
+    FOR EACH ROW
+EXECUTE FUNCTION record_trip_purchase();
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER "Shopping_Item_item_history" ON "Shopping_Item";
+DROP FUNCTION record_item_history();
+DROP FUNCTION item_history_key(Text);
+
+DROP TABLE "Item_History";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,79 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE "Item_History"
+(
+    "User_id"    BigInt REFERENCES "User" ("ID") ON DELETE CASCADE NOT NULL,
+    "Title"      Character varying(100)                            NOT NULL,
+    "Last_title" Character varying(100)                            NOT NULL,
+    "Category"   Character varying(30)                             NOT NULL,
+    "Quantity"   Numeric(12, 3)                                    NOT NULL,
+    "Unit"       Character varying(10)                             NOT NULL,
+    "Price"      BigInt                                            NOT NULL,
+    "Currency"   Character varying(3)                              NOT NULL,
+    "Uses"       Integer                                           NOT NULL DEFAULT 1,
+    "Last_used"  Timestamp with time zone                          NOT NULL DEFAULT now(),
+    PRIMARY KEY ("User_id", "Title")
+) WITH (autovacuum_enabled = true);
+
+CREATE INDEX "Item_History_Title_trgm_idx" ON "Item_History" USING gin ("Title" gin_trgm_ops);
+
+-- The title of the history is the title of items in lower case with single spaces and
+-- е instead of ё, matching service.autocompleteKey.
+CREATE FUNCTION item_history_key(title Text) RETURNS Text AS
+$$
+SELECT translate(lower(regexp_replace(btrim(title), '\s+', ' ', 'g')), 'ё', 'е');
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Adding an item or renaming it is a use of its title, other changes keep the history up to date.
+-- The price is the actual price, the estimated one without it, and the previous one without both.
+CREATE FUNCTION record_item_history() RETURNS trigger AS
+$$
+DECLARE
+    list RECORD;
+    used Boolean;
+BEGIN
+    used := TG_OP = 'INSERT' OR item_history_key(OLD."Title") <> item_history_key(NEW."Title");
+
+    SELECT L."User_id", L."Currency" INTO list FROM "Shopping_List" L WHERE L."ID" = NEW."List_id";
+
+    INSERT INTO "Item_History" AS H ("User_id", "Title", "Last_title", "Category", "Quantity", "Unit", "Price", "Currency")
+    VALUES (list."User_id", item_history_key(NEW."Title"), btrim(NEW."Title"), NEW."Category", NEW."Quantity",
+            NEW."Unit", COALESCE(NULLIF(NEW."Actual_price", 0), NEW."Estimated_price"), list."Currency")
+    ON CONFLICT ("User_id", "Title") DO UPDATE
+        SET "Last_title" = EXCLUDED."Last_title",
+            "Category"   = EXCLUDED."Category",
+            "Quantity"   = EXCLUDED."Quantity",
+            "Unit"       = EXCLUDED."Unit",
+            "Price"      = CASE WHEN EXCLUDED."Price" = 0 THEN H."Price" ELSE EXCLUDED."Price" END,
+            "Currency"   = CASE WHEN EXCLUDED."Price" = 0 THEN H."Currency" ELSE EXCLUDED."Currency" END,
+            "Uses"       = H."Uses" + used::Integer,
+            "Last_used"  = CASE WHEN used THEN now() ELSE H."Last_used" END;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "Shopping_Item_item_history"
+    AFTER INSERT OR UPDATE OF "Title", "Category", "Quantity", "Unit", "Estimated_price", "Actual_price"
+    ON "Shopping_Item"
+    FOR EACH ROW
+EXECUTE FUNCTION record_item_history();
+
+INSERT INTO "Item_History" ("User_id", "Title", "Last_title", "Category", "Quantity", "Unit", "Price", "Currency", "Uses")
+SELECT L."User_id",
+       item_history_key(I."Title"),
+       (array_agg(btrim(I."Title") ORDER BY I."ID" DESC))[1],
+       (array_agg(I."Category" ORDER BY I."ID" DESC))[1],
+       (array_agg(I."Quantity" ORDER BY I."ID" DESC))[1],
+       (array_agg(I."Unit" ORDER BY I."ID" DESC))[1],
+       COALESCE((array_agg(COALESCE(NULLIF(I."Actual_price", 0), I."Estimated_price") ORDER BY I."ID" DESC)
+                 FILTER (WHERE I."Actual_price" <> 0 OR I."Estimated_price" <> 0))[1], 0),
+       (array_agg(L."Currency" ORDER BY I."ID" DESC))[1],
+       count(*)
+FROM "Shopping_Item" I
+         INNER JOIN "Shopping_List" L on I."List_id" = L."ID"
+WHERE btrim(I."Title") <> ''
+GROUP BY L."User_id", item_history_key(I."Title");
+
+COMMIT;
\ No newline at end of file
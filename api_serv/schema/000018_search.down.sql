@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX "Shopping_Item_Title_trgm_idx";
+DROP INDEX "Shopping_List_Title_trgm_idx";
+
+ALTER TABLE "Shopping_Item"
+    DROP COLUMN "Search";
+ALTER TABLE "Shopping_List"
+    DROP COLUMN "Search";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Titles weigh more than descriptions, both are stemmed as Russian and English text.
+ALTER TABLE "Shopping_List"
+    ADD COLUMN "Search" tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', "Title"), 'A') ||
+        setweight(to_tsvector('english', "Title"), 'A') ||
+        setweight(to_tsvector('russian', COALESCE("Description", '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE("Description", '')), 'B')) STORED;
+
+ALTER TABLE "Shopping_Item"
+    ADD COLUMN "Search" tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', "Title"), 'A') ||
+        setweight(to_tsvector('english', "Title"), 'A') ||
+        setweight(to_tsvector('russian', COALESCE("Description", '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE("Description", '')), 'B')) STORED;
+
+CREATE INDEX "Shopping_List_Search_idx" ON "Shopping_List" USING gin ("Search");
+CREATE INDEX "Shopping_Item_Search_idx" ON "Shopping_Item" USING gin ("Search");
+CREATE INDEX "Shopping_List_Title_trgm_idx" ON "Shopping_List" USING gin ("Title" gin_trgm_ops);
+CREATE INDEX "Shopping_Item_Title_trgm_idx" ON "Shopping_Item" USING gin ("Title" gin_trgm_ops);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX "Change_Log_User_id_Xid_idx";
+
+ALTER TABLE "Change_Log"
+    DROP COLUMN "Xid";
+
+COMMIT;
\ No newline at end of file
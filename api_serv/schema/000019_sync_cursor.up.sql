@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Transaction that wrote the change. Pull cursors are snapshot xmins: every change of a
+-- transaction older than the cursor is committed or rolled back, so none is skipped.
+-- Existing changes get the ID of this transaction and are sent again on the next pull.
+ALTER TABLE "Change_Log"
+    ADD COLUMN "Xid" xid8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX "Change_Log_User_id_Xid_idx" ON "Change_Log" ("User_id", "Xid");
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE "Template_Item"
+    DROP COLUMN "Unit",
+    DROP COLUMN "Category";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE "Template_Item"
+    ADD COLUMN "Unit"     Character varying(10) NOT NULL DEFAULT 'pcs',
+    ADD COLUMN "Category" Character varying(30) NOT NULL DEFAULT '';
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE "Snapshot_Item"
+    DROP COLUMN "Quantity",
+    DROP COLUMN "Unit",
+    DROP COLUMN "Category",
+    DROP COLUMN "Estimated_price",
+    DROP COLUMN "Actual_price";
+
+COMMIT;
\ No newline at end of file
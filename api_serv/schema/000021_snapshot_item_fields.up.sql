@@ -0,0 +1,20 @@
+BEGIN;
+
+ALTER TABLE "Snapshot_Item"
+    ADD COLUMN "Quantity"        Numeric(12, 3)        NOT NULL DEFAULT 1,
+    ADD COLUMN "Unit"            Character varying(10) NOT NULL DEFAULT 'pcs',
+    ADD COLUMN "Category"        Character varying(30) NOT NULL DEFAULT '',
+    ADD COLUMN "Estimated_price" BigInt                NOT NULL DEFAULT 0,
+    ADD COLUMN "Actual_price"    BigInt                NOT NULL DEFAULT 0;
+
+-- Existing snapshots did not store these fields, the current values of the items are the best guess.
+UPDATE "Snapshot_Item" SI
+SET "Quantity"        = I."Quantity",
+    "Unit"            = I."Unit",
+    "Category"        = I."Category",
+    "Estimated_price" = I."Estimated_price",
+    "Actual_price"    = I."Actual_price"
+FROM "Shopping_Item" I
+WHERE I."ID" = SI."Item_id";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "Trip"
+    DROP COLUMN "Shopper";
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Who did the shopping, the user who started the trip by default
+ALTER TABLE "Trip"
+    ADD COLUMN "Shopper" Character varying(50) NOT NULL DEFAULT '';
+
+UPDATE "Trip" T
+SET "Shopper" = U."Username"
+FROM "User" U
+WHERE U."ID" = T."User_id";
+
+ALTER TABLE "Trip"
+    ALTER COLUMN "Shopper" DROP DEFAULT;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,44 @@
+BEGIN;
+
+-- Items checked off within three hours belong to one walk through the store. The rank
+-- moves towards the latest position, recent walks weigh at least a tenth. Only items checked
+-- off one by one in a transaction setting shopping_list.learn_store_order are learned from:
+-- bulk checks, finished trips and sync pushes update items in the order of their IDs.
+CREATE OR REPLACE FUNCTION learn_store_order() RETURNS trigger AS
+$$
+DECLARE
+    store          BigInt;
+    checked_before Integer;
+BEGIN
+    IF NOT NEW."Checked" THEN
+        NEW."Checked_at" := NULL;
+        RETURN NEW;
+    END IF;
+    IF OLD."Checked" THEN
+        RETURN NEW;
+    END IF;
+
+    NEW."Checked_at" := now();
+
+    IF current_setting('shopping_list.learn_store_order', true) IS DISTINCT FROM 'on' THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT L."Store_id" INTO store FROM "Shopping_List" L WHERE L."ID" = NEW."List_id";
+    IF store IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT count(*) INTO checked_before FROM "Shopping_Item" I
+    WHERE I."List_id" = NEW."List_id" AND I."ID" <> NEW."ID" AND I."Checked" AND I."Checked_at" > now() - interval '3 hours';
+
+    INSERT INTO "Store_Rank" ("Store_id", "Title", "Rank")
+    VALUES (store, lower(regexp_replace(btrim(NEW."Title"), '\s+', ' ', 'g')), checked_before)
+    ON CONFLICT ("Store_id", "Title") DO UPDATE
+        SET "Rank"    = "Store_Rank"."Rank" + (EXCLUDED."Rank" - "Store_Rank"."Rank") / LEAST("Store_Rank"."Samples" + 1, 10),
+            "Samples" = "Store_Rank"."Samples" + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMIT;
\ No newline at end of file